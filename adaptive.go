@@ -0,0 +1,128 @@
+package jlcpcb
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AdaptiveConfig contains adaptive rate limiter configuration.
+type AdaptiveConfig struct {
+	InitialRate      float64       // Starting rate in requests per second
+	MinRate          float64       // Lowest rate the limiter backs off to
+	MaxRate          float64       // Highest rate the limiter recovers to
+	DecreaseFactor   float64       // Multiplier applied to the rate when throttled
+	IncreaseStep     float64       // Requests per second added on recovery
+	RecoveryInterval time.Duration // Minimum time between rate changes before recovering
+	Clock            Clock         // Time source; nil uses the system clock
+}
+
+// DefaultAdaptiveConfig returns a default adaptive rate limiter configuration.
+func DefaultAdaptiveConfig() AdaptiveConfig {
+	return AdaptiveConfig{
+		InitialRate:      defaultRateLimit,
+		MinRate:          0.2,
+		MaxRate:          defaultRateLimit,
+		DecreaseFactor:   0.5,
+		IncreaseStep:     0.5,
+		RecoveryInterval: 5 * time.Second,
+	}
+}
+
+// AdaptiveLimiter is a token bucket whose rate follows an additive-increase,
+// multiplicative-decrease policy. A 429 or 503 response cuts the rate by
+// DecreaseFactor; successful responses raise it by IncreaseStep at most once
+// per RecoveryInterval. Throttling responses arriving within RecoveryInterval
+// of the last decrease are treated as part of the same episode.
+type AdaptiveLimiter struct {
+	mu           sync.Mutex
+	config       AdaptiveConfig
+	bucket       *RateLimiter
+	rate         float64
+	lastChange   time.Time
+	lastDecrease time.Time
+}
+
+var _ FeedbackLimiter = (*AdaptiveLimiter)(nil)
+
+// NewAdaptiveLimiter creates an adaptive rate limiter. Zero fields in config
+// are taken from DefaultAdaptiveConfig.
+func NewAdaptiveLimiter(config AdaptiveConfig) *AdaptiveLimiter {
+	defaults := DefaultAdaptiveConfig()
+	if config.InitialRate <= 0 {
+		config.InitialRate = defaults.InitialRate
+	}
+	if config.MaxRate <= 0 {
+		config.MaxRate = max(config.InitialRate, defaults.MaxRate)
+	}
+	if config.MinRate <= 0 {
+		config.MinRate = defaults.MinRate
+	}
+	if config.MinRate > config.MaxRate {
+		config.MinRate = config.MaxRate
+	}
+	if config.DecreaseFactor <= 0 || config.DecreaseFactor >= 1 {
+		config.DecreaseFactor = defaults.DecreaseFactor
+	}
+	if config.IncreaseStep <= 0 {
+		config.IncreaseStep = defaults.IncreaseStep
+	}
+	if config.RecoveryInterval <= 0 {
+		config.RecoveryInterval = defaults.RecoveryInterval
+	}
+	if config.Clock == nil {
+		config.Clock = systemClock{}
+	}
+
+	rate := min(max(config.InitialRate, config.MinRate), config.MaxRate)
+	return &AdaptiveLimiter{
+		config:     config,
+		bucket:     NewRateLimiterWithConfig(RateLimiterConfig{Rate: rate, Clock: config.Clock}),
+		rate:       rate,
+		lastChange: config.Clock.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent at the current rate.
+func (al *AdaptiveLimiter) Wait(ctx context.Context) error {
+	return al.bucket.Wait(ctx)
+}
+
+// Feedback adjusts the rate based on the status code of a response.
+func (al *AdaptiveLimiter) Feedback(statusCode int) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	now := al.config.Clock.Now()
+	switch {
+	case statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable:
+		if !al.lastDecrease.IsZero() && now.Sub(al.lastDecrease) < al.config.RecoveryInterval {
+			return
+		}
+		al.setRate(max(al.rate*al.config.DecreaseFactor, al.config.MinRate), now)
+		al.lastDecrease = now
+	case statusCode >= 200 && statusCode < 300:
+		if al.rate >= al.config.MaxRate || now.Sub(al.lastChange) < al.config.RecoveryInterval {
+			return
+		}
+		al.setRate(min(al.rate+al.config.IncreaseStep, al.config.MaxRate), now)
+	}
+}
+
+// Rate returns the current rate in requests per second.
+func (al *AdaptiveLimiter) Rate() float64 {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	return al.rate
+}
+
+// setRate applies a new rate to the underlying bucket.
+// Must be called with mu held.
+func (al *AdaptiveLimiter) setRate(rps float64, now time.Time) {
+	al.rate = rps
+	al.lastChange = now
+	al.bucket.SetRate(rps)
+	al.bucket.SetBurst(defaultBurst(rps))
+}
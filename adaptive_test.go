@@ -0,0 +1,172 @@
+package jlcpcb
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newTestAdaptiveLimiter creates an adaptive limiter driven by a fake clock.
+func newTestAdaptiveLimiter() (*AdaptiveLimiter, *fakeClock) {
+	clock := newFakeClock()
+	return NewAdaptiveLimiter(AdaptiveConfig{
+		InitialRate:      4,
+		MinRate:          0.5,
+		MaxRate:          4,
+		DecreaseFactor:   0.5,
+		IncreaseStep:     1,
+		RecoveryInterval: 10 * time.Second,
+		Clock:            clock,
+	}), clock
+}
+
+// TestNewAdaptiveLimiterDefaults tests that zero config fields get defaults.
+func TestNewAdaptiveLimiterDefaults(t *testing.T) {
+	al := NewAdaptiveLimiter(AdaptiveConfig{})
+	defaults := DefaultAdaptiveConfig()
+
+	if al.Rate() != defaults.InitialRate {
+		t.Errorf("expected rate %f, got %f", defaults.InitialRate, al.Rate())
+	}
+	if al.config.MinRate != defaults.MinRate || al.config.MaxRate != defaults.MaxRate {
+		t.Errorf("unexpected bounds: %+v", al.config)
+	}
+	if al.config.Clock == nil {
+		t.Error("expected default clock")
+	}
+}
+
+// TestAdaptiveLimiterBacksOff tests multiplicative decrease on throttling.
+func TestAdaptiveLimiterBacksOff(t *testing.T) {
+	al, clock := newTestAdaptiveLimiter()
+
+	al.Feedback(http.StatusTooManyRequests)
+	if al.Rate() != 2 {
+		t.Fatalf("expected rate 2 after 429, got %f", al.Rate())
+	}
+
+	// Throttling within the same episode is ignored.
+	al.Feedback(http.StatusTooManyRequests)
+	if al.Rate() != 2 {
+		t.Fatalf("expected rate to stay 2 within the recovery interval, got %f", al.Rate())
+	}
+
+	for _, want := range []float64{1, 0.5, 0.5} {
+		clock.Advance(10 * time.Second)
+		al.Feedback(http.StatusServiceUnavailable)
+		if al.Rate() != want {
+			t.Errorf("expected rate %f, got %f", want, al.Rate())
+		}
+	}
+}
+
+// TestAdaptiveLimiterRecovers tests additive increase on success.
+func TestAdaptiveLimiterRecovers(t *testing.T) {
+	al, clock := newTestAdaptiveLimiter()
+
+	al.Feedback(http.StatusTooManyRequests)
+	clock.Advance(10 * time.Second)
+	al.Feedback(http.StatusTooManyRequests)
+	if al.Rate() != 1 {
+		t.Fatalf("expected rate 1, got %f", al.Rate())
+	}
+
+	al.Feedback(http.StatusOK)
+	if al.Rate() != 1 {
+		t.Errorf("expected no recovery before the interval, got %f", al.Rate())
+	}
+
+	for _, want := range []float64{2, 3, 4, 4} {
+		clock.Advance(10 * time.Second)
+		al.Feedback(http.StatusOK)
+		if al.Rate() != want {
+			t.Errorf("expected rate %f, got %f", want, al.Rate())
+		}
+	}
+}
+
+// TestAdaptiveLimiterIgnoresOtherStatus tests that other errors leave the rate alone.
+func TestAdaptiveLimiterIgnoresOtherStatus(t *testing.T) {
+	al, _ := newTestAdaptiveLimiter()
+
+	al.Feedback(http.StatusInternalServerError)
+	al.Feedback(http.StatusNotFound)
+	if al.Rate() != 4 {
+		t.Errorf("expected rate 4, got %f", al.Rate())
+	}
+}
+
+// TestAdaptiveLimiterWaitFollowsRate tests that Wait paces requests at the lowered rate.
+func TestAdaptiveLimiterWaitFollowsRate(t *testing.T) {
+	al, clock := newTestAdaptiveLimiter()
+	ctx := context.Background()
+
+	al.Feedback(http.StatusTooManyRequests)
+	clock.Advance(10 * time.Second)
+	al.Feedback(http.StatusTooManyRequests)
+	clock.Advance(10 * time.Second)
+	al.Feedback(http.StatusTooManyRequests)
+	if al.Rate() != 0.5 {
+		t.Fatalf("expected rate 0.5, got %f", al.Rate())
+	}
+
+	if err := al.Wait(ctx); err != nil {
+		t.Fatalf("initial wait failed: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- al.Wait(ctx) }()
+
+	// At 0.5 rps the next token takes two seconds.
+	clock.BlockUntil(t, 1)
+	clock.Advance(time.Second)
+	clock.BlockUntil(t, 1)
+	select {
+	case <-done:
+		t.Fatal("expected Wait to block for two seconds")
+	default:
+	}
+
+	clock.Advance(2 * time.Second)
+	if err := <-done; err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+}
+
+// TestClientAdaptiveRateLimit tests that the client reports responses to the limiter.
+func TestClientAdaptiveRateLimit(t *testing.T) {
+	server := newFlakyServer(t, 1, Product{ComponentCode: "C1"})
+	clock := newFakeClock()
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRetryConfig(fastRetryConfig()),
+		WithAdaptiveRateLimit(AdaptiveConfig{InitialRate: 4, MaxRate: 4, Clock: clock}),
+	)
+
+	if _, err := client.KeywordSearch(context.Background(), SearchRequest{Keyword: "C1"}); err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+
+	al, ok := client.rateLimiter.(*AdaptiveLimiter)
+	if !ok {
+		t.Fatalf("expected *AdaptiveLimiter, got %T", client.rateLimiter)
+	}
+	if al.Rate() != 2 {
+		t.Errorf("expected rate 2 after a 503, got %f", al.Rate())
+	}
+}
+
+// TestWithLimiter tests installing a custom limiter.
+func TestWithLimiter(t *testing.T) {
+	custom := NewRateLimiter(1)
+	client := NewClient(WithLimiter(custom))
+	if client.rateLimiter != custom {
+		t.Error("expected custom limiter")
+	}
+
+	client = NewClient(WithLimiter(nil))
+	if client.rateLimiter == nil {
+		t.Error("expected nil limiter to keep the default")
+	}
+}
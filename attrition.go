@@ -0,0 +1,157 @@
+package jlcpcb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+)
+
+// AttritionRule sets the extra parts JLCPCB consumes for machine loss when
+// placing parts of a package or category. A rule matches a product when
+// every pattern it sets matches.
+type AttritionRule struct {
+	Package  string  `json:"package,omitempty"`  // Regular expression matched against ComponentSpecificationEn
+	Category string  `json:"category,omitempty"` // Regular expression matched against FirstSortName or SecondSortName
+	Extra    int     `json:"extra,omitempty"`    // Fixed extra parts per order
+	Percent  float64 `json:"percent,omitempty"`  // Extra parts as a percentage of the quantity, rounded up
+}
+
+// AttritionModel estimates the extra parts consumed by machine loss. Rules
+// are tried in the order they were added; products no rule matches use
+// their LossNumber. A nil model uses LossNumber for every product.
+type AttritionModel struct {
+	rules []attritionRule
+}
+
+// attritionRule is a compiled attrition rule.
+type attritionRule struct {
+	pkg      *regexp.Regexp
+	category *regexp.Regexp
+	rule     AttritionRule
+}
+
+// NewAttritionModel creates a model from rules.
+func NewAttritionModel(rules ...AttritionRule) (*AttritionModel, error) {
+	m := &AttritionModel{}
+	if err := m.Add(rules...); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Add appends rules to the model.
+func (m *AttritionModel) Add(rules ...AttritionRule) error {
+	for _, rule := range rules {
+		if rule.Package == "" && rule.Category == "" {
+			return fmt.Errorf("attrition rule sets neither package nor category")
+		}
+		if rule.Extra < 0 || rule.Percent < 0 {
+			return fmt.Errorf("attrition rule for %q has negative extra parts", rule.Package+rule.Category)
+		}
+
+		compiled := attritionRule{rule: rule}
+		var err error
+		if rule.Package != "" {
+			if compiled.pkg, err = regexp.Compile("(?i)" + rule.Package); err != nil {
+				return fmt.Errorf("invalid package pattern %q: %w", rule.Package, err)
+			}
+		}
+		if rule.Category != "" {
+			if compiled.category, err = regexp.Compile("(?i)" + rule.Category); err != nil {
+				return fmt.Errorf("invalid category pattern %q: %w", rule.Category, err)
+			}
+		}
+		m.rules = append(m.rules, compiled)
+	}
+	return nil
+}
+
+// Lookup returns the first rule matching a product, and whether any did.
+func (m *AttritionModel) Lookup(p *Product) (AttritionRule, bool) {
+	if m == nil {
+		return AttritionRule{}, false
+	}
+	for _, r := range m.rules {
+		if r.pkg != nil && !r.pkg.MatchString(p.ComponentSpecificationEn) {
+			continue
+		}
+		if r.category != nil && !r.category.MatchString(p.FirstSortName) && !r.category.MatchString(p.SecondSortName) {
+			continue
+		}
+		return r.rule, true
+	}
+	return AttritionRule{}, false
+}
+
+// Extra returns the extra parts consumed when placing quantity parts. Its
+// signature matches StockOptions.Attrition.
+func (m *AttritionModel) Extra(p *Product, quantity int) int {
+	rule, ok := m.Lookup(p)
+	if !ok {
+		return p.LossNumber
+	}
+	return rule.Extra + int(math.Ceil(float64(quantity)*rule.Percent/100-1e-9))
+}
+
+// ReadAttritionRules reads rules from a JSON array such as
+//
+//	[
+//	  {"package": "^0402$", "extra": 10, "percent": 1},
+//	  {"category": "^Crystals", "extra": 2}
+//	]
+func ReadAttritionRules(r io.Reader) ([]AttritionRule, error) {
+	var rules []AttritionRule
+	if err := json.NewDecoder(r).Decode(&rules); err != nil {
+		return nil, fmt.Errorf("failed to parse attrition rules: %w", err)
+	}
+	return rules, nil
+}
+
+// LoadAttritionModel reads a JSON rule file into a new model that falls
+// back to DefaultAttritionRules. Rules in the file take precedence.
+func LoadAttritionModel(path string) (*AttritionModel, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open attrition rules: %w", err)
+	}
+	defer f.Close()
+
+	rules, err := ReadAttritionRules(f)
+	if err != nil {
+		return nil, err
+	}
+	return NewAttritionModel(append(rules, DefaultAttritionRules()...)...)
+}
+
+// DefaultAttritionRules returns approximate loss rules for chip passives,
+// where small packages lose the most parts to the feeders. Check JLCPCB's
+// current figures before relying on them for costing.
+func DefaultAttritionRules() []AttritionRule {
+	return []AttritionRule{
+		{Package: `^(01005|0201)$`, Extra: 20, Percent: 2},
+		{Package: `^0402$`, Extra: 10, Percent: 1},
+		{Package: `^0603$`, Extra: 5, Percent: 1},
+		{Package: `^(0805|1206|1210)$`, Extra: 5, Percent: 0.5},
+	}
+}
+
+// OrderQuantity returns the parts to order to place quantity parts: the
+// quantity plus attrition per model, at least the minimum order quantity.
+// A nil model uses the product's LossNumber.
+func (p *Product) OrderQuantity(quantity int, model *AttritionModel) int {
+	return max(quantity+model.Extra(p, quantity), p.MinPurchaseNum)
+}
+
+// OrderPrice returns the total price of placing quantity parts, ordering
+// OrderQuantity parts at the price break that quantity reaches.
+func (p *Product) OrderPrice(quantity int, model *AttritionModel) (Money, bool) {
+	return p.ExtendedPrice(p.OrderQuantity(quantity, model))
+}
+
+// HasStockFor reports whether the stock covers OrderQuantity parts.
+func (p *Product) HasStockFor(quantity int, model *AttritionModel) bool {
+	return p.StockCount >= p.OrderQuantity(quantity, model)
+}
@@ -0,0 +1,125 @@
+package jlcpcb
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAttritionModel tests rule matching and extra part counts.
+func TestAttritionModel(t *testing.T) {
+	model, err := NewAttritionModel(
+		AttritionRule{Package: `^0402$`, Category: `resistor`, Extra: 10, Percent: 1},
+		AttritionRule{Package: `^0402$`, Extra: 20},
+		AttritionRule{Category: `^Crystals`, Extra: 2},
+	)
+	if err != nil {
+		t.Fatalf("NewAttritionModel failed: %v", err)
+	}
+
+	resistor := &Product{ComponentSpecificationEn: "0402", FirstSortName: "Resistors", LossNumber: 99}
+	capacitor := &Product{ComponentSpecificationEn: "0402", FirstSortName: "Capacitors"}
+	crystal := &Product{ComponentSpecificationEn: "SMD3225-4P", SecondSortName: "Crystals"}
+	other := &Product{ComponentSpecificationEn: "SOT-23", LossNumber: 3}
+
+	tests := []struct {
+		name     string
+		product  *Product
+		quantity int
+		want     int
+	}{
+		{"package and category", resistor, 1000, 20},
+		{"percent rounds up", resistor, 1001, 21},
+		{"package only", capacitor, 1000, 20},
+		{"secondary category", crystal, 1000, 2},
+		{"loss number fallback", other, 1000, 3},
+	}
+	for _, tt := range tests {
+		if got := model.Extra(tt.product, tt.quantity); got != tt.want {
+			t.Errorf("%s: Extra = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	var nilModel *AttritionModel
+	if got := nilModel.Extra(resistor, 1000); got != 99 {
+		t.Errorf("expected a nil model to use LossNumber, got %d", got)
+	}
+}
+
+// TestAttritionModelInvalid tests rejecting invalid rules.
+func TestAttritionModelInvalid(t *testing.T) {
+	for _, rule := range []AttritionRule{
+		{Extra: 5},
+		{Package: "(", Extra: 5},
+		{Category: "[", Extra: 5},
+		{Package: "0402", Extra: -1},
+	} {
+		if _, err := NewAttritionModel(rule); err == nil {
+			t.Errorf("expected an error for %+v", rule)
+		}
+	}
+}
+
+// TestLoadAttritionModel tests file rules taking precedence over defaults.
+func TestLoadAttritionModel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attrition.json")
+	data := `[{"package": "^0402$", "extra": 50}]`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	model, err := LoadAttritionModel(path)
+	if err != nil {
+		t.Fatalf("LoadAttritionModel failed: %v", err)
+	}
+	if got := model.Extra(&Product{ComponentSpecificationEn: "0402"}, 1000); got != 50 {
+		t.Errorf("expected the file rule, got %d", got)
+	}
+	if got := model.Extra(&Product{ComponentSpecificationEn: "0603"}, 1000); got != 15 {
+		t.Errorf("expected the default 0603 rule, got %d", got)
+	}
+
+	if _, err := ReadAttritionRules(strings.NewReader("{")); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+	if _, err := LoadAttritionModel(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+// TestProductOrderQuantity tests the order helpers with attrition.
+func TestProductOrderQuantity(t *testing.T) {
+	model, err := NewAttritionModel(DefaultAttritionRules()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Product{
+		ComponentSpecificationEn: "0402",
+		StockCount:               1015,
+		MinPurchaseNum:           100,
+		ComponentPrices: []PriceBreak{
+			{StartNumber: 100, ProductPrice: MoneyFromFloat(0.002, "USD")},
+			{StartNumber: 1000, ProductPrice: MoneyFromFloat(0.001, "USD")},
+		},
+	}
+
+	if got := p.OrderQuantity(10, model); got != 100 {
+		t.Errorf("expected the MOQ for small quantities, got %d", got)
+	}
+	if got := p.OrderQuantity(1000, model); got != 1020 {
+		t.Errorf("expected 1000 plus 10 plus 1%%, got %d", got)
+	}
+	if got := p.OrderQuantity(1000, nil); got != 1000 {
+		t.Errorf("expected no attrition without a model or LossNumber, got %d", got)
+	}
+
+	// 990 placed parts reach the 1000 price break once attrition is added.
+	price, ok := p.OrderPrice(990, model)
+	if !ok || price.Decimal() != "1.01" {
+		t.Errorf("expected 1010 parts at 0.001, got %v", price)
+	}
+	if p.HasStockFor(1000, model) || !p.HasStockFor(1000, nil) {
+		t.Error("expected attrition to exceed the stock of 1015")
+	}
+}
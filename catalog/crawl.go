@@ -0,0 +1,48 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+// CrawlOptions contains crawl configuration.
+type CrawlOptions struct {
+	// Queries are the searches to page through. To crawl a category, set
+	// SortBy and SortBySecondary to its first- and second-level names.
+	Queries  []jlcpcb.SearchRequest
+	PageSize int // Results per page (default and max: 100)
+	MaxPages int // Maximum pages per query (0 for no limit)
+	// Progress, if set, is called after each fetched page.
+	Progress func(query jlcpcb.SearchRequest, page, fetched, total int)
+}
+
+// Crawl pages through every query and adds the results to idx. It returns
+// the number of products fetched. The index is not saved; call idx.Save.
+func Crawl(ctx context.Context, src jlcpcb.PartsAPI, idx *Index, opts CrawlOptions) (int, error) {
+	fetched := 0
+	for _, query := range opts.Queries {
+		query.PageSize = opts.PageSize
+		query.CurrentPage = 1
+		pager := jlcpcb.NewPager(src, query)
+		queryFetched := 0
+
+		for (opts.MaxPages <= 0 || pager.PageNumber() < opts.MaxPages) && pager.Next(ctx) {
+			resp := pager.Page()
+			idx.Add(resp.Products...)
+			fetched += len(resp.Products)
+			queryFetched += len(resp.Products)
+
+			if opts.Progress != nil {
+				query.CurrentPage, query.PageSize = pager.PageNumber(), pager.PageSize()
+				opts.Progress(query, pager.PageNumber(), queryFetched, resp.TotalCount)
+			}
+		}
+		if err := pager.Err(); err != nil {
+			return fetched, fmt.Errorf("crawl %q page %d: %w", query.Keyword, pager.PageNumber(), err)
+		}
+	}
+
+	return fetched, nil
+}
@@ -0,0 +1,79 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+// errSearcher is a PartsAPI that always fails.
+type errSearcher struct{}
+
+func (errSearcher) KeywordSearch(ctx context.Context, req jlcpcb.SearchRequest) (*jlcpcb.SearchResponse, error) {
+	return nil, errors.New("offline")
+}
+
+func (errSearcher) GetProductDetails(ctx context.Context, partCode string) (*jlcpcb.Product, error) {
+	return nil, errors.New("offline")
+}
+
+// TestCrawl tests that a crawl pages through all results into the index.
+func TestCrawl(t *testing.T) {
+	src := NewLocalClient(newTestIndex(t))
+	idx := NewIndex(filepath.Join(t.TempDir(), "mirror.jsonl"))
+
+	pages := 0
+	n, err := Crawl(context.Background(), src, idx, CrawlOptions{
+		Queries:  []jlcpcb.SearchRequest{{Keyword: "resistors"}, {Keyword: "0402"}},
+		PageSize: 1,
+		Progress: func(query jlcpcb.SearchRequest, page, fetched, total int) {
+			pages++
+		},
+	})
+	if err != nil {
+		t.Fatalf("Crawl failed: %v", err)
+	}
+
+	if n != 4 {
+		t.Errorf("expected 4 fetched products, got %d", n)
+	}
+	if pages != 4 {
+		t.Errorf("expected 4 pages, got %d", pages)
+	}
+	if idx.Len() != 3 {
+		t.Errorf("expected 3 distinct products, got %d", idx.Len())
+	}
+}
+
+// TestCrawlMaxPages tests that MaxPages bounds each query.
+func TestCrawlMaxPages(t *testing.T) {
+	src := NewLocalClient(newTestIndex(t))
+	idx := NewIndex(filepath.Join(t.TempDir(), "mirror.jsonl"))
+
+	n, err := Crawl(context.Background(), src, idx, CrawlOptions{
+		Queries:  []jlcpcb.SearchRequest{{Keyword: "resistors"}},
+		PageSize: 1,
+		MaxPages: 1,
+	})
+	if err != nil {
+		t.Fatalf("Crawl failed: %v", err)
+	}
+	if n != 1 || idx.Len() != 1 {
+		t.Errorf("expected a single page to be crawled, got %d products", n)
+	}
+}
+
+// TestCrawlError tests that search errors stop the crawl.
+func TestCrawlError(t *testing.T) {
+	idx := NewIndex(filepath.Join(t.TempDir(), "mirror.jsonl"))
+
+	_, err := Crawl(context.Background(), errSearcher{}, idx, CrawlOptions{
+		Queries: []jlcpcb.SearchRequest{{Keyword: "resistors"}},
+	})
+	if err == nil {
+		t.Fatal("expected crawl error")
+	}
+}
@@ -0,0 +1,288 @@
+// Package catalog maintains an offline mirror of the JLCPCB parts catalog.
+//
+// A crawl pages through KeywordSearch results and stores every product in a
+// local Index. LocalClient then answers KeywordSearch and GetProductDetails
+// from that index, so tools can run without network access.
+package catalog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+	"github.com/PatrickWalther/go-jlcpcb-parts/internal/search"
+)
+
+// Index is an in-memory full-text index of products persisted as a file of
+// JSON lines.
+type Index struct {
+	mu       sync.RWMutex
+	path     string
+	products []jlcpcb.Product
+	byCode   map[string]int
+	postings map[string][]int // token -> product positions, ascending
+	vocab    []string         // sorted tokens, for prefix lookups
+	dirty    bool             // vocab needs rebuilding
+}
+
+// NewIndex creates an empty index that will be saved to path.
+func NewIndex(path string) *Index {
+	return &Index{
+		path:     path,
+		byCode:   make(map[string]int),
+		postings: make(map[string][]int),
+	}
+}
+
+// Open loads the index stored at path. A missing file yields an empty index.
+func Open(path string) (*Index, error) {
+	idx := NewIndex(path)
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return idx, nil
+		}
+		return nil, fmt.Errorf("failed to open index: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var p jlcpcb.Product
+		if err := json.Unmarshal(line, &p); err != nil {
+			return nil, fmt.Errorf("failed to parse index entry: %w", err)
+		}
+		idx.add(p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	return idx, nil
+}
+
+// Path returns the file the index is saved to.
+func (idx *Index) Path() string {
+	return idx.path
+}
+
+// Len returns the number of indexed products.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return len(idx.products)
+}
+
+// Add inserts or replaces products, keyed by component code.
+func (idx *Index) Add(products ...jlcpcb.Product) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, p := range products {
+		idx.add(p)
+	}
+}
+
+// Get returns the product with the given component code.
+func (idx *Index) Get(code string) (jlcpcb.Product, bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	pos, ok := idx.byCode[search.NormalizeCode(code)]
+	if !ok {
+		return jlcpcb.Product{}, false
+	}
+	return idx.products[pos], true
+}
+
+// Save writes the index to its path, replacing any previous contents.
+func (idx *Index) Save() error {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	if dir := filepath.Dir(idx.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create index directory: %w", err)
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(idx.path), filepath.Base(idx.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create index file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for i := range idx.products {
+		if err := enc.Encode(&idx.products[i]); err != nil {
+			_ = tmp.Close()
+			return fmt.Errorf("failed to write index entry: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), idx.path); err != nil {
+		return fmt.Errorf("failed to replace index: %w", err)
+	}
+	return nil
+}
+
+// add inserts or replaces a product.
+// Must be called with mu held.
+func (idx *Index) add(p jlcpcb.Product) {
+	code := search.NormalizeCode(p.ComponentCode)
+	if code == "" {
+		return
+	}
+
+	if pos, ok := idx.byCode[code]; ok {
+		idx.removePostings(pos)
+		idx.products[pos] = p
+		idx.addPostings(pos)
+		return
+	}
+
+	pos := len(idx.products)
+	idx.products = append(idx.products, p)
+	idx.byCode[code] = pos
+	idx.addPostings(pos)
+}
+
+// addPostings indexes the tokens of the product at pos.
+// Must be called with mu held.
+func (idx *Index) addPostings(pos int) {
+	for _, tok := range search.ProductTokens(&idx.products[pos]) {
+		list := idx.postings[tok]
+		if len(list) == 0 {
+			idx.dirty = true
+		}
+		i := sort.SearchInts(list, pos)
+		list = append(list, 0)
+		copy(list[i+1:], list[i:])
+		list[i] = pos
+		idx.postings[tok] = list
+	}
+}
+
+// removePostings removes the tokens of the product at pos from the index.
+// Must be called with mu held.
+func (idx *Index) removePostings(pos int) {
+	for _, tok := range search.ProductTokens(&idx.products[pos]) {
+		list := idx.postings[tok]
+		i := sort.SearchInts(list, pos)
+		if i < len(list) && list[i] == pos {
+			list = append(list[:i], list[i+1:]...)
+		}
+		if len(list) == 0 {
+			delete(idx.postings, tok)
+			idx.dirty = true
+			continue
+		}
+		idx.postings[tok] = list
+	}
+}
+
+// matchLocked returns the positions of products containing every query token
+// as a token prefix, ranked by the number of exact token matches.
+// Must be called with mu held, after buildVocab.
+func (idx *Index) matchLocked(keyword string) []int {
+	// An exact part code match always ranks first.
+	if pos, ok := idx.byCode[search.NormalizeCode(keyword)]; ok {
+		return []int{pos}
+	}
+
+	query := search.Tokenize(keyword)
+	if len(query) == 0 {
+		return nil
+	}
+
+	var candidates map[int]int // position -> exact match count
+	for _, q := range query {
+		hits := make(map[int]int)
+		lo := sort.SearchStrings(idx.vocab, q)
+		for i := lo; i < len(idx.vocab) && strings.HasPrefix(idx.vocab[i], q); i++ {
+			exact := 0
+			if idx.vocab[i] == q {
+				exact = 1
+			}
+			for _, pos := range idx.postings[idx.vocab[i]] {
+				if hits[pos] < exact+1 {
+					hits[pos] = exact + 1
+				}
+			}
+		}
+
+		if candidates == nil {
+			candidates = make(map[int]int, len(hits))
+			for pos, h := range hits {
+				candidates[pos] = h - 1
+			}
+			continue
+		}
+		for pos := range candidates {
+			h, ok := hits[pos]
+			if !ok {
+				delete(candidates, pos)
+				continue
+			}
+			candidates[pos] += h - 1
+		}
+	}
+
+	result := make([]int, 0, len(candidates))
+	for pos := range candidates {
+		result = append(result, pos)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		si, sj := candidates[result[i]], candidates[result[j]]
+		if si != sj {
+			return si > sj
+		}
+		return result[i] < result[j]
+	})
+	return result
+}
+
+// buildVocab rebuilds the sorted token list if tokens were added or removed.
+func (idx *Index) buildVocab() {
+	idx.mu.RLock()
+	dirty := idx.dirty
+	idx.mu.RUnlock()
+	if !dirty {
+		return
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if !idx.dirty {
+		return
+	}
+	idx.vocab = idx.vocab[:0]
+	for tok := range idx.postings {
+		idx.vocab = append(idx.vocab, tok)
+	}
+	sort.Strings(idx.vocab)
+	idx.dirty = false
+}
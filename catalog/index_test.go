@@ -0,0 +1,171 @@
+package catalog
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+// testProducts returns a small set of products for index tests.
+func testProducts() []jlcpcb.Product {
+	return []jlcpcb.Product{
+		{
+			ComponentCode:            "C25744",
+			ComponentModelEn:         "0402WGF1002TCE",
+			ComponentBrandEn:         "UNI-ROYAL(Uniroyal Elec)",
+			ComponentSpecificationEn: "0402",
+			FirstSortName:            "Resistors",
+			SecondSortName:           "Chip Resistor - Surface Mount",
+			Describe:                 "10kΩ ±1% 62.5mW 0402 Thick Film Resistors",
+			StockCount:               1000000,
+			Attributes:               []jlcpcb.Attribute{{Name: "Resistance", Value: "10kΩ"}},
+		},
+		{
+			ComponentCode:            "C25804",
+			ComponentModelEn:         "0603WAF1002T5E",
+			ComponentBrandEn:         "UNI-ROYAL(Uniroyal Elec)",
+			ComponentSpecificationEn: "0603",
+			FirstSortName:            "Resistors",
+			SecondSortName:           "Chip Resistor - Surface Mount",
+			Describe:                 "10kΩ ±1% 100mW 0603 Thick Film Resistors",
+			StockCount:               0,
+			Attributes:               []jlcpcb.Attribute{{Name: "Resistance", Value: "10kΩ"}},
+		},
+		{
+			ComponentCode:            "C1525",
+			ComponentModelEn:         "CL05B104KO5NNNC",
+			ComponentBrandEn:         "Samsung Electro-Mechanics",
+			ComponentSpecificationEn: "0402",
+			FirstSortName:            "Capacitors",
+			SecondSortName:           "Multilayer Ceramic Capacitors MLCC - SMD/SMT",
+			Describe:                 "16V 100nF X7R ±10% 0402 Multilayer Ceramic Capacitors",
+			StockCount:               500000,
+			Attributes:               []jlcpcb.Attribute{{Name: "Capacitance", Value: "100nF"}},
+		},
+		{
+			ComponentCode:            "C5676715",
+			ComponentModelEn:         "MPM3506AGQV-Z",
+			ComponentBrandEn:         "Monolithic Power Systems",
+			ComponentSpecificationEn: "QFN-19(3x5)",
+			FirstSortName:            "Power Management ICs",
+			SecondSortName:           "DC-DC Power Modules",
+			StockCount:               549,
+		},
+	}
+}
+
+// newTestIndex creates an index populated with testProducts.
+func newTestIndex(t *testing.T) *Index {
+	t.Helper()
+
+	idx := NewIndex(filepath.Join(t.TempDir(), "catalog.jsonl"))
+	idx.Add(testProducts()...)
+	return idx
+}
+
+// TestIndexAddAndGet tests adding and retrieving products.
+func TestIndexAddAndGet(t *testing.T) {
+	idx := newTestIndex(t)
+
+	if idx.Len() != 4 {
+		t.Fatalf("expected 4 products, got %d", idx.Len())
+	}
+
+	p, ok := idx.Get("c1525")
+	if !ok {
+		t.Fatal("expected to find C1525 case-insensitively")
+	}
+	if p.ComponentModelEn != "CL05B104KO5NNNC" {
+		t.Errorf("unexpected product: %s", p.ComponentModelEn)
+	}
+
+	if _, ok := idx.Get("C1"); ok {
+		t.Error("expected miss for unknown code")
+	}
+}
+
+// TestIndexAddReplaces tests that re-adding a product replaces it and its tokens.
+func TestIndexAddReplaces(t *testing.T) {
+	idx := newTestIndex(t)
+
+	updated := testProducts()[3]
+	updated.ComponentModelEn = "RENAMED"
+	idx.Add(updated)
+
+	if idx.Len() != 4 {
+		t.Fatalf("expected replacement not to grow index, got %d", idx.Len())
+	}
+
+	idx.buildVocab()
+	if got := idx.matchLocked("mpm3506"); len(got) != 0 {
+		t.Errorf("expected old tokens to be removed, got %v", got)
+	}
+	if got := idx.matchLocked("renamed"); len(got) != 1 {
+		t.Errorf("expected new tokens to be indexed, got %v", got)
+	}
+}
+
+// TestIndexSaveAndOpen tests that an index round-trips through its file.
+func TestIndexSaveAndOpen(t *testing.T) {
+	idx := newTestIndex(t)
+
+	if err := idx.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := Open(idx.Path())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	if loaded.Len() != idx.Len() {
+		t.Fatalf("expected %d products, got %d", idx.Len(), loaded.Len())
+	}
+
+	want, _ := idx.Get("C25744")
+	got, _ := loaded.Get("C25744")
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("product mismatch after reload:\nwant %+v\ngot  %+v", want, got)
+	}
+
+	loaded.buildVocab()
+	if len(loaded.matchLocked("resistors")) != 2 {
+		t.Error("expected reloaded index to be searchable")
+	}
+}
+
+// TestOpenMissing tests that opening a missing file yields an empty index.
+func TestOpenMissing(t *testing.T) {
+	idx, err := Open(filepath.Join(t.TempDir(), "missing.jsonl"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if idx.Len() != 0 {
+		t.Errorf("expected empty index, got %d", idx.Len())
+	}
+}
+
+// TestIndexMatchRanking tests prefix matching and ranking.
+func TestIndexMatchRanking(t *testing.T) {
+	idx := newTestIndex(t)
+	idx.buildVocab()
+
+	if got := idx.matchLocked("C5676715"); len(got) != 1 || got[0] != 3 {
+		t.Errorf("expected exact code match, got %v", got)
+	}
+
+	if got := idx.matchLocked("MPM3506"); len(got) != 1 || got[0] != 3 {
+		t.Errorf("expected prefix match on MPN, got %v", got)
+	}
+
+	got := idx.matchLocked("10k 0603")
+	if len(got) != 1 || got[0] != 1 {
+		t.Errorf("expected all tokens to be required, got %v", got)
+	}
+
+	if got := idx.matchLocked("nonexistent"); len(got) != 0 {
+		t.Errorf("expected no matches, got %v", got)
+	}
+}
@@ -0,0 +1,79 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+	"github.com/PatrickWalther/go-jlcpcb-parts/internal/search"
+)
+
+var _ jlcpcb.PartsAPI = (*LocalClient)(nil)
+
+// LocalClient answers product searches from an Index. Its methods have the
+// same signatures and defaults as the online jlcpcb.Client.
+type LocalClient struct {
+	index *Index
+}
+
+// NewLocalClient creates a client that searches the given index.
+func NewLocalClient(index *Index) *LocalClient {
+	return &LocalClient{index: index}
+}
+
+// KeywordSearch searches the index by keyword with optional filters.
+// Every keyword token must prefix-match a token of the product's code,
+// model, brand, name, package, description, categories or attribute values.
+func (lc *LocalClient) KeywordSearch(ctx context.Context, req jlcpcb.SearchRequest) (*jlcpcb.SearchResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := search.Normalize(&req); err != nil {
+		return nil, err
+	}
+
+	lc.index.buildVocab()
+	lc.index.mu.RLock()
+	defer lc.index.mu.RUnlock()
+
+	var matched []int
+	for _, pos := range lc.index.matchLocked(req.Keyword) {
+		if search.MatchesFilters(&lc.index.products[pos], &req) {
+			matched = append(matched, pos)
+		}
+	}
+
+	resp := &jlcpcb.SearchResponse{
+		Products:   []jlcpcb.Product{},
+		TotalCount: len(matched),
+		PageSize:   req.PageSize,
+		PageNumber: req.CurrentPage,
+	}
+
+	start, end := search.PageBounds(len(matched), req.CurrentPage, req.PageSize)
+	for _, pos := range matched[start:end] {
+		resp.Products = append(resp.Products, lc.index.products[pos])
+	}
+
+	return resp, nil
+}
+
+// GetProductDetails retrieves a product from the index by part code.
+func (lc *LocalClient) GetProductDetails(ctx context.Context, partCode string) (*jlcpcb.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	partCode = strings.TrimSpace(partCode)
+	if partCode == "" {
+		return nil, fmt.Errorf("part code is required")
+	}
+
+	product, ok := lc.index.Get(partCode)
+	if !ok {
+		return nil, jlcpcb.ErrProductNotFound{ProductCode: partCode}
+	}
+	return &product, nil
+}
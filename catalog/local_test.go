@@ -0,0 +1,130 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+// TestLocalKeywordSearch tests keyword search over the index.
+func TestLocalKeywordSearch(t *testing.T) {
+	client := NewLocalClient(newTestIndex(t))
+
+	resp, err := client.KeywordSearch(context.Background(), jlcpcb.SearchRequest{Keyword: "0402"})
+	if err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+
+	if resp.TotalCount != 2 || len(resp.Products) != 2 {
+		t.Fatalf("expected 2 results, got total %d, len %d", resp.TotalCount, len(resp.Products))
+	}
+	if resp.PageNumber != 1 || resp.PageSize != 50 {
+		t.Errorf("expected default paging, got page %d size %d", resp.PageNumber, resp.PageSize)
+	}
+}
+
+// TestLocalKeywordSearchEmptyKeyword tests that an empty keyword is rejected.
+func TestLocalKeywordSearchEmptyKeyword(t *testing.T) {
+	client := NewLocalClient(newTestIndex(t))
+
+	if _, err := client.KeywordSearch(context.Background(), jlcpcb.SearchRequest{Keyword: "  "}); err == nil {
+		t.Fatal("expected error for empty keyword")
+	}
+}
+
+// TestLocalKeywordSearchFilters tests attribute, package, brand and stock filters.
+func TestLocalKeywordSearchFilters(t *testing.T) {
+	client := NewLocalClient(newTestIndex(t))
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		req  jlcpcb.SearchRequest
+		want []string
+	}{
+		{"package", jlcpcb.SearchRequest{Keyword: "resistors", Packages: []string{"0603"}}, []string{"C25804"}},
+		{"stock", jlcpcb.SearchRequest{Keyword: "resistors", StockOnly: true}, []string{"C25744"}},
+		{"attribute", jlcpcb.SearchRequest{Keyword: "0402", Attributes: []jlcpcb.FilterAttribute{{Name: "capacitance", Value: "100nF"}}}, []string{"C1525"}},
+		{"brand", jlcpcb.SearchRequest{Keyword: "0402", Brands: []string{"samsung electro-mechanics"}}, []string{"C1525"}},
+		{"category", jlcpcb.SearchRequest{Keyword: "0402", SortBy: "Resistors"}, []string{"C25744"}},
+	}
+
+	for _, test := range tests {
+		resp, err := client.KeywordSearch(ctx, test.req)
+		if err != nil {
+			t.Errorf("%s: KeywordSearch failed: %v", test.name, err)
+			continue
+		}
+		var got []string
+		for _, p := range resp.Products {
+			got = append(got, p.ComponentCode)
+		}
+		if len(got) != len(test.want) || (len(got) > 0 && got[0] != test.want[0]) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.want, got)
+		}
+	}
+}
+
+// TestLocalKeywordSearchPagination tests paging through results.
+func TestLocalKeywordSearchPagination(t *testing.T) {
+	client := NewLocalClient(newTestIndex(t))
+	ctx := context.Background()
+
+	seen := make(map[string]bool)
+	for page := 1; page <= 3; page++ {
+		resp, err := client.KeywordSearch(ctx, jlcpcb.SearchRequest{Keyword: "resistors", CurrentPage: page, PageSize: 1})
+		if err != nil {
+			t.Fatalf("KeywordSearch failed: %v", err)
+		}
+		if resp.TotalCount != 2 {
+			t.Errorf("expected total 2, got %d", resp.TotalCount)
+		}
+		for _, p := range resp.Products {
+			seen[p.ComponentCode] = true
+		}
+		if page == 3 && len(resp.Products) != 0 {
+			t.Errorf("expected empty page past the end, got %d", len(resp.Products))
+		}
+	}
+
+	if len(seen) != 2 {
+		t.Errorf("expected 2 distinct products across pages, got %d", len(seen))
+	}
+}
+
+// TestLocalGetProductDetails tests product lookup by code.
+func TestLocalGetProductDetails(t *testing.T) {
+	client := NewLocalClient(newTestIndex(t))
+	ctx := context.Background()
+
+	p, err := client.GetProductDetails(ctx, " C1525 ")
+	if err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+	if p.ComponentCode != "C1525" {
+		t.Errorf("expected C1525, got %s", p.ComponentCode)
+	}
+
+	_, err = client.GetProductDetails(ctx, "C99999999")
+	var notFound jlcpcb.ErrProductNotFound
+	if !errors.As(err, &notFound) {
+		t.Errorf("expected ErrProductNotFound, got %v", err)
+	}
+
+	if _, err := client.GetProductDetails(ctx, ""); err == nil {
+		t.Error("expected error for empty part code")
+	}
+}
+
+// TestLocalContextCancellation tests that a cancelled context is respected.
+func TestLocalContextCancellation(t *testing.T) {
+	client := NewLocalClient(newTestIndex(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.KeywordSearch(ctx, jlcpcb.SearchRequest{Keyword: "0402"}); err == nil {
+		t.Fatal("expected error for cancelled context")
+	}
+}
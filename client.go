@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"time"
@@ -22,14 +23,31 @@ const (
 
 // Client is a JLCPCB Parts API client.
 type Client struct {
-	httpClient  *http.Client
-	baseURL     string
-	currency    string
-	rateLimiter *RateLimiter
-	cache       Cache
-	retryConfig RetryConfig
+	httpClient    *http.Client
+	baseURL       string
+	currency      string
+	rateLimiter   Limiter
+	cache         Cache
+	retryConfig   RetryConfig
+	snapshots     SnapshotStore
+	observer      Observer
+	logger        *slog.Logger
+	logConfig     LogConfig
+	flights       flightGroup
+	exchangeRates *ExchangeRates
 }
 
+// PartsAPI is the set of part lookup operations offered by Client.
+// Alternate backends, such as catalog.LocalClient and jlcpcbtest.Fake,
+// implement it so callers can swap them for the online client.
+// Methods may be added to PartsAPI as Client grows.
+type PartsAPI interface {
+	KeywordSearch(ctx context.Context, req SearchRequest) (*SearchResponse, error)
+	GetProductDetails(ctx context.Context, partCode string) (*Product, error)
+}
+
+var _ PartsAPI = (*Client)(nil)
+
 // ClientOption is a function that configures a Client.
 type ClientOption func(*Client)
 
@@ -47,10 +65,20 @@ func WithBaseURL(baseURL string) ClientOption {
 	}
 }
 
-// WithCurrency sets the currency for price responses.
+// WithCurrency sets the currency for price responses. Requests fail with
+// ErrInvalidInput if the code is not one of SupportedCurrencies.
 func WithCurrency(currency string) ClientOption {
 	return func(c *Client) {
-		c.currency = currency
+		c.currency = normalizeCurrency(currency)
+	}
+}
+
+// WithExchangeRates converts prices locally instead of asking the API for
+// the configured currency. Prices are requested in rates.Base and multiplied
+// by the rate for the client's currency.
+func WithExchangeRates(rates ExchangeRates) ClientOption {
+	return func(c *Client) {
+		c.exchangeRates = &rates
 	}
 }
 
@@ -61,6 +89,24 @@ func WithRateLimit(rps float64) ClientOption {
 	}
 }
 
+// WithAdaptiveRateLimit uses an AdaptiveLimiter that slows down when the
+// API responds with 429 or 503 and recovers on success.
+func WithAdaptiveRateLimit(config AdaptiveConfig) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter = NewAdaptiveLimiter(config)
+	}
+}
+
+// WithLimiter sets a custom rate limiter. If the limiter implements
+// FeedbackLimiter it receives the status code of every response.
+func WithLimiter(limiter Limiter) ClientOption {
+	return func(c *Client) {
+		if limiter != nil {
+			c.rateLimiter = limiter
+		}
+	}
+}
+
 // WithCache sets a cache for API responses.
 func WithCache(cache Cache) ClientOption {
 	return func(c *Client) {
@@ -75,6 +121,24 @@ func WithRetryConfig(config RetryConfig) ClientOption {
 	}
 }
 
+// WithSnapshotStore records a snapshot of every product fetched from the API.
+func WithSnapshotStore(store SnapshotStore) ClientOption {
+	return func(c *Client) {
+		c.snapshots = store
+	}
+}
+
+// WithObserver sets an observer that receives request, retry, cache and
+// rate limiter events.
+func WithObserver(observer Observer) ClientOption {
+	return func(c *Client) {
+		if observer == nil {
+			observer = NopObserver{}
+		}
+		c.observer = observer
+	}
+}
+
 // NewClient creates a new JLCPCB Parts API client.
 func NewClient(opts ...ClientOption) *Client {
 	c := &Client{
@@ -85,6 +149,8 @@ func NewClient(opts ...ClientOption) *Client {
 		currency:    defaultCurrency,
 		rateLimiter: NewRateLimiter(defaultRateLimit),
 		retryConfig: DefaultRetryConfig(),
+		observer:    NopObserver{},
+		logger:      slog.New(discardHandler{}),
 	}
 
 	for _, opt := range opts {
@@ -96,43 +162,101 @@ func NewClient(opts ...ClientOption) *Client {
 
 // doRequest performs an HTTP request to the JLCPCB API.
 func (c *Client) doRequest(ctx context.Context, method, path string, params url.Values, body interface{}) ([]byte, error) {
+	ctx, logger := c.withRequestLogger(ctx)
+
 	cacheKey := ""
 	if method == http.MethodGet && c.cache != nil {
 		cacheKey = c.buildCacheKey(method, path, params)
 		if cached, ok := c.cache.Get(cacheKey); ok {
+			c.noteCache(ctx, logger, cacheKey, true)
 			return cached, nil
 		}
+		c.noteCache(ctx, logger, cacheKey, false)
+	}
+
+	respBody, err := c.withRetries(ctx, method, path, func(ctx context.Context) ([]byte, int, error) {
+		return c.executeRequest(ctx, method, path, params, body)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheKey != "" && c.cache != nil {
+		c.cache.Set(cacheKey, respBody, 5*time.Minute)
 	}
 
+	return respBody, nil
+}
+
+// withRetries runs attempt under the client's rate limiter and retry policy,
+// reporting every attempt to the observer and logger.
+func (c *Client) withRetries(ctx context.Context, method, path string, attempt func(ctx context.Context) ([]byte, int, error)) ([]byte, error) {
+	ctx, logger := c.withRequestLogger(ctx)
+	requestStart := time.Now()
+
+	logger.DebugContext(ctx, "request started", slog.String("method", method), slog.String("path", path))
+
 	var lastErr error
-	for attempt := 0; attempt <= c.retryConfig.MaxRetries; attempt++ {
-		if attempt > 0 {
-			waitTime := c.retryConfig.calculateBackoff(attempt - 1)
+	for n := 0; n <= c.retryConfig.MaxRetries; n++ {
+		if n > 0 {
+			waitTime := c.retryConfig.calculateBackoff(n - 1)
+			c.observer.Retry(RetryInfo{Method: method, Path: path, Attempt: n, Wait: waitTime, Err: lastErr})
+			logger.WarnContext(ctx, "retrying request",
+				slog.String("method", method),
+				slog.String("path", path),
+				slog.Int("attempt", n),
+				slog.Duration("wait", waitTime),
+				slog.Any("error", lastErr))
 			if err := sleep(ctx, waitTime); err != nil {
 				return nil, err
 			}
 		}
 
+		waitStart := time.Now()
 		if err := c.rateLimiter.Wait(ctx); err != nil {
 			return nil, fmt.Errorf("rate limiter: %w", err)
 		}
-
-		respBody, statusCode, err := c.executeRequest(ctx, method, path, params, body)
+		c.observer.RateLimitWait(time.Since(waitStart))
+
+		info := RequestInfo{Method: method, Path: path, Attempt: n}
+		c.observer.RequestStart(info)
+		start := time.Now()
+		respBody, statusCode, err := attempt(ctx)
+		info.Status, info.Duration, info.Err = statusCode, time.Since(start), err
+		c.observer.RequestEnd(info)
+		c.limiterFeedback(statusCode)
 		if err != nil {
 			lastErr = err
 			if shouldRetry(err, statusCode) {
 				continue
 			}
+			logger.WarnContext(ctx, "request failed",
+				slog.String("method", method),
+				slog.String("path", path),
+				slog.Int("status", statusCode),
+				slog.Int("attempts", n+1),
+				slog.Duration("latency", time.Since(requestStart)),
+				slog.Any("error", err))
 			return nil, err
 		}
 
-		if cacheKey != "" && c.cache != nil {
-			c.cache.Set(cacheKey, respBody, 5*time.Minute)
-		}
+		logger.DebugContext(ctx, "request completed",
+			slog.String("method", method),
+			slog.String("path", path),
+			slog.Int("status", statusCode),
+			slog.Int("attempts", n+1),
+			slog.Duration("latency", time.Since(requestStart)))
 
 		return respBody, nil
 	}
 
+	logger.WarnContext(ctx, "request failed",
+		slog.String("method", method),
+		slog.String("path", path),
+		slog.Int("attempts", c.retryConfig.MaxRetries+1),
+		slog.Duration("latency", time.Since(requestStart)),
+		slog.Any("error", lastErr))
+
 	return nil, fmt.Errorf("max retries exceeded: %w", lastErr)
 }
 
@@ -164,40 +288,92 @@ func (c *Client) executeRequest(ctx context.Context, method, path string, params
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
 	req.Header.Set("Origin", "https://jlcpcb.com")
 	req.Header.Set("Referer", "https://jlcpcb.com/parts")
+	req.AddCookie(&http.Cookie{Name: currencyCookie, Value: c.requestCurrency()})
 
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
+	_, logger := c.withRequestLogger(ctx)
+	c.logHeaders(ctx, logger, req.Header)
+
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		logger.DebugContext(ctx, "http request error",
+			slog.Duration("latency", time.Since(start)),
+			slog.Any("error", err))
 		return nil, 0, fmt.Errorf("request failed: %w", err)
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 
+	respBody, err := readBody(resp, -1)
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+
+	logger.DebugContext(ctx, "http response",
+		slog.Int("status", resp.StatusCode),
+		slog.Int("bytes", len(respBody)),
+		slog.Bool("gzip", resp.Header.Get("Content-Encoding") == "gzip"),
+		slog.Duration("latency", time.Since(start)))
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return respBody, resp.StatusCode, nil
+}
+
+// limiterFeedback reports a response status code to the rate limiter.
+// Transport errors, which have no status code, are not reported.
+func (c *Client) limiterFeedback(statusCode int) {
+	if statusCode == 0 {
+		return
+	}
+	if fl, ok := c.rateLimiter.(FeedbackLimiter); ok {
+		fl.Feedback(statusCode)
+	}
+}
+
+// readBody reads a response body, decompressing gzip. A non-negative limit
+// caps the decoded size.
+func readBody(resp *http.Response, limit int64) ([]byte, error) {
 	// Handle gzip compression
 	var readCloser io.ReadCloser = resp.Body
 	if resp.Header.Get("Content-Encoding") == "gzip" {
 		var err error
 		readCloser, err = gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, resp.StatusCode, fmt.Errorf("failed to create gzip reader: %w", err)
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
 		}
 		defer readCloser.Close()
 	}
 
-	respBody, err := io.ReadAll(readCloser)
+	var reader io.Reader = readCloser
+	if limit >= 0 {
+		reader = io.LimitReader(readCloser, limit+1)
+	}
+	body, err := io.ReadAll(reader)
 	if err != nil {
-		return nil, resp.StatusCode, fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, resp.StatusCode, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	if limit >= 0 && int64(len(body)) > limit {
+		return nil, fmt.Errorf("response exceeds %d bytes", limit)
 	}
+	return body, nil
+}
 
-	return respBody, resp.StatusCode, nil
+// noteCache reports a cache lookup to the observer and logger.
+func (c *Client) noteCache(ctx context.Context, logger *slog.Logger, key string, hit bool) {
+	if hit {
+		c.observer.CacheHit(key)
+	} else {
+		c.observer.CacheMiss(key)
+	}
+	c.logCache(ctx, logger, key, hit)
 }
 
 // buildCacheKey creates a cache key from request parameters.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -315,3 +316,24 @@ func contains(s, substring string) bool {
 	}
 	return false
 }
+
+// newSearchServer starts a test server that answers every search with the given products.
+func newSearchServer(t *testing.T, products ...Product) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var wrapper productSearchWrapper
+		wrapper.Code = 200
+		wrapper.Data.ComponentPageInfo = SearchResponse{
+			Products:   products,
+			TotalCount: len(products),
+			PageSize:   len(products),
+			PageNumber: 1,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(wrapper)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
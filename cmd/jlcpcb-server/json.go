@@ -0,0 +1,113 @@
+package main
+
+import (
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+// The types below define the server's response schema. They are kept
+// separate from jlcpcb.Product so that upstream field changes do not change
+// what clients see. Fields are only ever added.
+
+// partJSON is a part in API responses.
+type partJSON struct {
+	Code         string          `json:"code"`
+	MPN          string          `json:"mpn"`
+	Manufacturer string          `json:"manufacturer"`
+	Description  string          `json:"description"`
+	Package      string          `json:"package"`
+	Category     string          `json:"category"`
+	Subcategory  string          `json:"subcategory"`
+	LibraryType  string          `json:"library_type"` // "basic" or "extended"
+	Preferred    bool            `json:"preferred"`
+	Stock        int             `json:"stock"`
+	MinOrder     int             `json:"min_order"`
+	Currency     string          `json:"currency"`
+	Prices       []priceJSON     `json:"prices"`
+	Attributes   []attributeJSON `json:"attributes"`
+	DatasheetURL string          `json:"datasheet_url"`
+	ImageURL     string          `json:"image_url"`
+	URL          string          `json:"url"`
+}
+
+// priceJSON is one price break. MaxQuantity is omitted for the last,
+// unbounded tier.
+type priceJSON struct {
+	MinQuantity int          `json:"min_qty"`
+	MaxQuantity *int         `json:"max_qty,omitempty"`
+	UnitPrice   jlcpcb.Money `json:"unit_price"`
+}
+
+// attributeJSON is one specification.
+type attributeJSON struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// searchJSON is the response of GET /search.
+type searchJSON struct {
+	Total    int        `json:"total"`
+	Page     int        `json:"page"`
+	PageSize int        `json:"page_size"`
+	Parts    []partJSON `json:"parts"`
+}
+
+// batchJSON is the response of POST /parts:batch.
+type batchJSON struct {
+	Results []batchResultJSON `json:"results"`
+}
+
+// batchResultJSON is the outcome of one lookup in a batch.
+type batchResultJSON struct {
+	Code  string           `json:"code"`
+	Part  *partJSON        `json:"part,omitempty"`
+	Error *errorDetailJSON `json:"error,omitempty"`
+}
+
+// errorJSON is the body of error responses.
+type errorJSON struct {
+	Error errorDetailJSON `json:"error"`
+}
+
+// errorDetailJSON describes an error with a stable machine-readable code.
+type errorDetailJSON struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// newPartJSON converts a product to its response form.
+func newPartJSON(p *jlcpcb.Product) partJSON {
+	part := partJSON{
+		Code:         p.ComponentCode,
+		MPN:          p.ComponentModelEn,
+		Manufacturer: p.ComponentBrandEn,
+		Description:  p.Describe,
+		Package:      p.ComponentSpecificationEn,
+		Category:     p.FirstSortName,
+		Subcategory:  p.SecondSortName,
+		LibraryType:  "extended",
+		Preferred:    p.IsPreferred(),
+		Stock:        p.StockCount,
+		MinOrder:     p.MinPurchaseNum,
+		Currency:     p.Currency,
+		Prices:       make([]priceJSON, 0, len(p.ComponentPrices)),
+		Attributes:   make([]attributeJSON, 0, len(p.Attributes)),
+		DatasheetURL: p.DataManualUrl,
+		ImageURL:     p.ImageURL(),
+		URL:          p.GetProductURL(),
+	}
+	if p.IsBasic() {
+		part.LibraryType = "basic"
+	}
+	for _, pb := range p.ComponentPrices {
+		price := priceJSON{MinQuantity: pb.StartNumber, UnitPrice: pb.ProductPrice}
+		if pb.EndNumber > 0 {
+			end := pb.EndNumber
+			price.MaxQuantity = &end
+		}
+		part.Prices = append(part.Prices, price)
+	}
+	for _, attr := range p.Attributes {
+		part.Attributes = append(part.Attributes, attributeJSON{Name: attr.Name, Value: attr.Value})
+	}
+	return part
+}
@@ -0,0 +1,116 @@
+// Command jlcpcb-server serves JLCPCB part data over a local HTTP/JSON API,
+// so tools outside Go share one client's cache and rate limit. With -mcp it
+// instead speaks the Model Context Protocol over stdin and stdout, offering
+// the tools search_parts, get_part and price_for_quantity.
+//
+// Usage:
+//
+//	jlcpcb-server [-addr :8080] [-rate 2] [-adaptive] [-currency USD]
+//	jlcpcb-server -mcp [-rate 2] [-currency USD]
+//
+// Endpoints:
+//
+//	GET  /search?q=keyword[&page=1&size=50&inStock=true&package=0402&brand=...&type=base]
+//	GET  /parts/{code}
+//	POST /parts:batch  {"codes": ["C25744", "C1525"]}
+//	GET  /healthz
+//	GET  /metrics      (expvar JSON)
+package main
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "jlcpcb-server:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses flags and serves until SIGINT or SIGTERM.
+func run() error {
+	addr := flag.String("addr", ":8080", "listen address")
+	rate := flag.Float64("rate", 2, "upstream requests per second")
+	adaptive := flag.Bool("adaptive", false, "back off automatically on 429 and 503 responses")
+	currency := flag.String("currency", "USD", "price currency")
+	timeout := flag.Duration("timeout", 30*time.Second, "per-request timeout")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 10*time.Second, "time allowed for in-flight requests on shutdown")
+	verbose := flag.Bool("v", false, "log client debug records")
+	mcp := flag.Bool("mcp", false, "serve MCP tools over stdin and stdout instead of HTTP")
+	flag.Parse()
+
+	if err := jlcpcb.ValidateCurrency(*currency); err != nil {
+		return err
+	}
+
+	level := slog.LevelInfo
+	if *verbose {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+
+	opts := []jlcpcb.ClientOption{
+		jlcpcb.WithCache(jlcpcb.NewMemoryCache()),
+		jlcpcb.WithCurrency(*currency),
+		jlcpcb.WithObserver(jlcpcb.NewExpvarObserver(expvar.NewMap("jlcpcb"))),
+		jlcpcb.WithLogger(logger),
+	}
+	if *adaptive {
+		cfg := jlcpcb.DefaultAdaptiveConfig()
+		cfg.InitialRate, cfg.MaxRate = *rate, *rate
+		cfg.MinRate = min(cfg.MinRate, *rate)
+		opts = append(opts, jlcpcb.WithAdaptiveRateLimit(cfg))
+	} else {
+		opts = append(opts, jlcpcb.WithRateLimit(*rate))
+	}
+	client := jlcpcb.NewClient(opts...)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *mcp {
+		return newMCPServer(client, logger, *timeout, os.Stdout).serve(ctx, os.Stdin)
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           newServer(client, logger, *timeout),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		logger.Info("listening", slog.String("addr", *addr))
+		errc <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down: %w", err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,540 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+// mcpProtocolVersion is the Model Context Protocol revision implemented.
+const mcpProtocolVersion = "2024-11-05"
+
+// JSON-RPC 2.0 error codes.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+// rpcRequest is a JSON-RPC request or notification. Notifications have no ID.
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcResponse is a JSON-RPC response.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC error object.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// mcpTool describes a tool in tools/list.
+type mcpTool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+}
+
+// mcpContent is one content block of a tool result.
+type mcpContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// mcpToolResult is the result of tools/call. Tool failures are reported
+// with IsError rather than as JSON-RPC errors, so the model can see them.
+type mcpToolResult struct {
+	Content []mcpContent `json:"content"`
+	IsError bool         `json:"isError,omitempty"`
+}
+
+// mcpTools lists the tools offered, in tools/list order.
+var mcpTools = []mcpTool{
+	{
+		Name:        "search_parts",
+		Description: "Search the JLCPCB parts library by keyword, such as a part number, value or description. Returns matching parts with stock, prices and specifications.",
+		InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "query": {"type": "string", "description": "Search keyword, e.g. \"10k 0402\" or \"RP2040\""},
+    "package": {"type": "string", "description": "Only parts in this package, e.g. \"0402\""},
+    "in_stock": {"type": "boolean", "description": "Only parts in stock"},
+    "basic_only": {"type": "boolean", "description": "Only basic library parts, which have no extended-part fee"},
+    "limit": {"type": "integer", "minimum": 1, "maximum": 50, "description": "Maximum number of parts to return (default 10)"}
+  },
+  "required": ["query"]
+}`),
+	},
+	{
+		Name:        "get_part",
+		Description: "Get full details of one JLCPCB part by its part code, such as C25744.",
+		InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "code": {"type": "string", "description": "JLCPCB/LCSC part code, e.g. \"C25744\""}
+  },
+  "required": ["code"]
+}`),
+	},
+	{
+		Name:        "price_for_quantity",
+		Description: "Get the unit and total price of ordering a quantity of one JLCPCB part, with the applicable price break, minimum order and stock.",
+		InputSchema: json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "code": {"type": "string", "description": "JLCPCB/LCSC part code, e.g. \"C25744\""},
+    "quantity": {"type": "integer", "minimum": 1, "description": "Number of parts to order"}
+  },
+  "required": ["code", "quantity"]
+}`),
+	},
+}
+
+// mcpServer serves MCP tools over newline-delimited JSON-RPC.
+type mcpServer struct {
+	api     jlcpcb.PartsAPI
+	logger  *slog.Logger
+	timeout time.Duration
+
+	writeMu sync.Mutex
+	enc     *json.Encoder
+
+	mu       sync.Mutex
+	inflight map[string]context.CancelFunc
+}
+
+// newMCPServer returns an MCP server writing responses to w. A zero
+// timeout means no limit per request.
+func newMCPServer(api jlcpcb.PartsAPI, logger *slog.Logger, timeout time.Duration, w io.Writer) *mcpServer {
+	return &mcpServer{
+		api:      api,
+		logger:   logger,
+		timeout:  timeout,
+		enc:      json.NewEncoder(w),
+		inflight: make(map[string]context.CancelFunc),
+	}
+}
+
+// serve reads requests from r until it is closed or ctx is done. Requests
+// are handled concurrently; responses may arrive out of order and are
+// matched by ID. serve waits for in-flight requests before returning.
+func (ms *mcpServer) serve(ctx context.Context, r io.Reader) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	lines := make(chan mcpLine)
+	errc := make(chan error, 1)
+	go func() {
+		br := bufio.NewReader(r)
+		for {
+			line, err := readLine(br, maxBodySize)
+			if len(line.data) > 0 || line.tooLong {
+				select {
+				case lines <- line:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				errc <- err
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			ms.cancelAll()
+			return nil
+		case err := <-errc:
+			return err
+		case line := <-lines:
+			if line.tooLong {
+				// Skip the message, answering it if its ID came early enough.
+				ms.logger.Warn("message too large", slog.Int("limit", maxBodySize))
+				if id := recoverID(line.data); id != nil {
+					ms.write(rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: rpcParseError, Message: "parse error: message too large"}})
+				}
+				continue
+			}
+			data := bytes.TrimSpace(line.data)
+			if len(data) == 0 {
+				continue
+			}
+			req, rpcErr := parseRPCRequest(data)
+			if rpcErr != nil {
+				ms.write(rpcResponse{JSONRPC: "2.0", ID: req.ID, Error: rpcErr})
+				continue
+			}
+			if req.ID == nil {
+				ms.handleNotification(req)
+				continue
+			}
+
+			reqCtx, cancel := ms.requestContext(ctx)
+			if !ms.track(req.ID, cancel) {
+				cancel()
+				ms.write(rpcResponse{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{Code: rpcInvalidRequest, Message: "duplicate request ID: " + string(req.ID)}})
+				continue
+			}
+			wg.Add(1)
+			go func(req rpcRequest) {
+				defer wg.Done()
+				result, rpcErr := ms.handle(reqCtx, req)
+				cancelled := errors.Is(reqCtx.Err(), context.Canceled)
+				// Release the ID before responding, so the client may reuse it.
+				ms.untrack(req.ID)
+				if cancelled && ctx.Err() == nil {
+					// Cancelled by the client, which expects no response.
+					return
+				}
+				ms.write(rpcResponse{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr})
+			}(req)
+		}
+	}
+}
+
+// mcpLine is one line read from the input. Lines over the size limit are
+// truncated to it and flagged.
+type mcpLine struct {
+	data    []byte
+	tooLong bool
+}
+
+// readLine reads one newline-terminated line of at most limit bytes. The
+// rest of a longer line is discarded.
+func readLine(br *bufio.Reader, limit int) (mcpLine, error) {
+	var line mcpLine
+	for {
+		chunk, err := br.ReadSlice('\n')
+		if room := limit - len(line.data); len(chunk) > room {
+			line.data = append(line.data, chunk[:max(room, 0)]...)
+			line.tooLong = true
+		} else {
+			line.data = append(line.data, chunk...)
+		}
+		if err != bufio.ErrBufferFull {
+			return line, err
+		}
+	}
+}
+
+// recoverID returns the ID of a truncated request if it appears before the
+// truncation, or nil.
+func recoverID(data []byte) json.RawMessage {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil
+		}
+		if key == "id" {
+			return value
+		}
+	}
+	return nil
+}
+
+// requestContext returns the context for one request, applying the timeout.
+func (ms *mcpServer) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ms.timeout > 0 {
+		return context.WithTimeout(ctx, ms.timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// parseRPCRequest decodes and validates one message.
+func parseRPCRequest(line []byte) (rpcRequest, *rpcError) {
+	var req rpcRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return rpcRequest{ID: json.RawMessage("null")}, &rpcError{Code: rpcInvalidRequest, Message: "invalid request"}
+		}
+		return rpcRequest{ID: json.RawMessage("null")}, &rpcError{Code: rpcParseError, Message: "parse error: " + err.Error()}
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		if req.ID == nil {
+			req.ID = json.RawMessage("null")
+		}
+		return req, &rpcError{Code: rpcInvalidRequest, Message: "invalid request"}
+	}
+	return req, nil
+}
+
+// handle dispatches a request and returns its result or error.
+func (ms *mcpServer) handle(ctx context.Context, req rpcRequest) (interface{}, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": mcpProtocolVersion,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]string{"name": "jlcpcb-server", "version": "1.0.0"},
+		}, nil
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": mcpTools}, nil
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: "invalid params: " + err.Error()}
+		}
+		return ms.callTool(ctx, params.Name, params.Arguments)
+	default:
+		return nil, &rpcError{Code: rpcMethodNotFound, Message: "method not found: " + req.Method}
+	}
+}
+
+// handleNotification handles a message without an ID.
+func (ms *mcpServer) handleNotification(req rpcRequest) {
+	switch req.Method {
+	case "notifications/cancelled":
+		var params struct {
+			RequestID json.RawMessage `json:"requestId"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err == nil {
+			ms.cancel(params.RequestID)
+		}
+	default:
+		// notifications/initialized and unknown notifications need no action.
+	}
+}
+
+// callTool runs a tool. Argument and lookup errors become tool results with
+// IsError set; only unknown tools are protocol errors.
+func (ms *mcpServer) callTool(ctx context.Context, name string, args json.RawMessage) (interface{}, *rpcError) {
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+
+	var result interface{}
+	var err error
+	switch name {
+	case "search_parts":
+		result, err = ms.searchParts(ctx, args)
+	case "get_part":
+		result, err = ms.getPart(ctx, args)
+	case "price_for_quantity":
+		result, err = ms.priceForQuantity(ctx, args)
+	default:
+		return nil, &rpcError{Code: rpcInvalidParams, Message: "unknown tool: " + name}
+	}
+
+	if err != nil {
+		ms.logger.Debug("tool failed", slog.String("tool", name), slog.Any("error", err))
+		return mcpToolResult{Content: []mcpContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	text, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+	}
+	return mcpToolResult{Content: []mcpContent{{Type: "text", Text: string(text)}}}, nil
+}
+
+// searchParts implements the search_parts tool.
+func (ms *mcpServer) searchParts(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		Query     string `json:"query"`
+		Package   string `json:"package"`
+		InStock   bool   `json:"in_stock"`
+		BasicOnly bool   `json:"basic_only"`
+		Limit     int    `json:"limit"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	if strings.TrimSpace(args.Query) == "" {
+		return nil, errors.New("query is required")
+	}
+	if args.Limit <= 0 {
+		args.Limit = 10
+	}
+	args.Limit = min(args.Limit, 50)
+
+	req := jlcpcb.SearchRequest{
+		Keyword:     args.Query,
+		CurrentPage: 1,
+		PageSize:    args.Limit,
+		IsAvailable: args.InStock,
+		StockOnly:   args.InStock,
+	}
+	if args.Package != "" {
+		req.Packages = []string{args.Package}
+	}
+	if args.BasicOnly {
+		req.ComponentType = "base"
+	}
+
+	resp, err := ms.api.KeywordSearch(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	parts := make([]partJSON, len(resp.Products))
+	for i := range resp.Products {
+		parts[i] = newPartJSON(&resp.Products[i])
+	}
+	return searchJSON{Total: resp.TotalCount, Page: 1, PageSize: args.Limit, Parts: parts}, nil
+}
+
+// getPart implements the get_part tool.
+func (ms *mcpServer) getPart(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	if strings.TrimSpace(args.Code) == "" {
+		return nil, errors.New("code is required")
+	}
+
+	product, err := ms.api.GetProductDetails(ctx, args.Code)
+	if err != nil {
+		return nil, err
+	}
+	return newPartJSON(product), nil
+}
+
+// quoteJSON is the result of the price_for_quantity tool.
+type quoteJSON struct {
+	Code          string       `json:"code"`
+	Quantity      int          `json:"quantity"`
+	Currency      string       `json:"currency"`
+	UnitPrice     jlcpcb.Money `json:"unit_price"`
+	ExtendedPrice jlcpcb.Money `json:"extended_price"`
+	PriceBreak    int          `json:"price_break"` // Starting quantity of the applied tier
+	MinOrder      int          `json:"min_order"`
+	MeetsMinOrder bool         `json:"meets_min_order"`
+	Stock         int          `json:"stock"`
+	InStock       bool         `json:"in_stock"` // Whether stock covers the quantity
+}
+
+// priceForQuantity implements the price_for_quantity tool.
+func (ms *mcpServer) priceForQuantity(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		Code     string `json:"code"`
+		Quantity int    `json:"quantity"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	if strings.TrimSpace(args.Code) == "" {
+		return nil, errors.New("code is required")
+	}
+	if args.Quantity < 1 {
+		return nil, errors.New("quantity must be at least 1")
+	}
+
+	product, err := ms.api.GetProductDetails(ctx, args.Code)
+	if err != nil {
+		return nil, err
+	}
+	unit, ok := product.UnitPrice(args.Quantity)
+	if !ok {
+		return nil, fmt.Errorf("part %s has no price breaks", product.ComponentCode)
+	}
+	extended, _ := product.ExtendedPrice(args.Quantity)
+
+	quote := quoteJSON{
+		Code:          product.ComponentCode,
+		Quantity:      args.Quantity,
+		Currency:      product.Currency,
+		UnitPrice:     unit,
+		ExtendedPrice: extended,
+		MinOrder:      product.MinPurchaseNum,
+		MeetsMinOrder: args.Quantity >= product.MinPurchaseNum,
+		Stock:         product.StockCount,
+		InStock:       product.StockCount >= args.Quantity,
+	}
+	for _, pb := range product.ComponentPrices {
+		if pb.StartNumber <= args.Quantity && pb.StartNumber > quote.PriceBreak {
+			quote.PriceBreak = pb.StartNumber
+		}
+	}
+	return quote, nil
+}
+
+// write sends one response.
+func (ms *mcpServer) write(resp rpcResponse) {
+	ms.writeMu.Lock()
+	defer ms.writeMu.Unlock()
+	if err := ms.enc.Encode(resp); err != nil {
+		ms.logger.Warn("failed to write response", slog.Any("error", err))
+	}
+}
+
+// track records the cancel function of an in-flight request. It reports
+// false if a request with the same ID is already in flight.
+func (ms *mcpServer) track(id json.RawMessage, cancel context.CancelFunc) bool {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if _, ok := ms.inflight[string(id)]; ok {
+		return false
+	}
+	ms.inflight[string(id)] = cancel
+	return true
+}
+
+// untrack releases an in-flight request.
+func (ms *mcpServer) untrack(id json.RawMessage) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if cancel, ok := ms.inflight[string(id)]; ok {
+		cancel()
+		delete(ms.inflight, string(id))
+	}
+}
+
+// cancel cancels an in-flight request by ID.
+func (ms *mcpServer) cancel(id json.RawMessage) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if cancel, ok := ms.inflight[string(bytes.TrimSpace(id))]; ok {
+		cancel()
+	}
+}
+
+// cancelAll cancels every in-flight request.
+func (ms *mcpServer) cancelAll() {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for _, cancel := range ms.inflight {
+		cancel()
+	}
+}
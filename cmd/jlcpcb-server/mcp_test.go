@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+	"github.com/PatrickWalther/go-jlcpcb-parts/jlcpcbtest"
+)
+
+// mcpSession drives an in-process MCP server over pipes.
+type mcpSession struct {
+	t      *testing.T
+	in     *io.PipeWriter
+	out    *bufio.Scanner
+	done   chan error
+	nextID int
+}
+
+// newMCPSession starts a server backed by a client pointed at a fake
+// JLCPCB API.
+func newMCPSession(t *testing.T, api *jlcpcbtest.Server) *mcpSession {
+	t.Helper()
+	client := jlcpcb.NewClient(jlcpcb.WithBaseURL(api.URL), jlcpcb.WithRateLimit(1000))
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	s := &mcpSession{t: t, in: inW, out: bufio.NewScanner(outR), done: make(chan error, 1)}
+
+	go func() {
+		err := newMCPServer(client, logger, 5*time.Second, outW).serve(context.Background(), inR)
+		outW.Close()
+		s.done <- err
+	}()
+	t.Cleanup(func() {
+		inW.Close()
+		for s.out.Scan() {
+		}
+		<-s.done
+	})
+	return s
+}
+
+// send writes one raw line to the server.
+func (s *mcpSession) send(line string) {
+	s.t.Helper()
+	if _, err := io.WriteString(s.in, line+"\n"); err != nil {
+		s.t.Fatalf("write failed: %v", err)
+	}
+}
+
+// read reads the next response.
+func (s *mcpSession) read() rpcTestResponse {
+	s.t.Helper()
+	if !s.out.Scan() {
+		s.t.Fatalf("no response: %v", s.out.Err())
+	}
+	var resp rpcTestResponse
+	if err := json.Unmarshal(s.out.Bytes(), &resp); err != nil {
+		s.t.Fatalf("invalid response %q: %v", s.out.Text(), err)
+	}
+	return resp
+}
+
+// call sends a request and returns its response.
+func (s *mcpSession) call(method string, params interface{}) rpcTestResponse {
+	s.t.Helper()
+	s.nextID++
+	data, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": s.nextID, "method": method, "params": params})
+	if err != nil {
+		s.t.Fatalf("marshal failed: %v", err)
+	}
+	s.send(string(data))
+	resp := s.read()
+	if string(resp.ID) != strings.TrimSpace(string(mustJSON(s.nextID))) {
+		s.t.Fatalf("expected response to %d, got %s", s.nextID, resp.ID)
+	}
+	return resp
+}
+
+// callTool calls a tool and returns its result.
+func (s *mcpSession) callTool(name string, args interface{}) mcpToolResult {
+	s.t.Helper()
+	resp := s.call("tools/call", map[string]interface{}{"name": name, "arguments": args})
+	if resp.Error != nil {
+		s.t.Fatalf("tools/call %s failed: %+v", name, resp.Error)
+	}
+	var result mcpToolResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		s.t.Fatalf("invalid tool result: %v", err)
+	}
+	if len(result.Content) != 1 || result.Content[0].Type != "text" {
+		s.t.Fatalf("expected one text content block, got %+v", result.Content)
+	}
+	return result
+}
+
+// rpcTestResponse is a decoded JSON-RPC response.
+type rpcTestResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// mustJSON marshals v, which must not fail.
+func mustJSON(v interface{}) []byte {
+	data, _ := json.Marshal(v)
+	return data
+}
+
+// TestMCPInitializeAndList tests the handshake and tool listing.
+func TestMCPInitializeAndList(t *testing.T) {
+	api := jlcpcbtest.NewServer(jlcpcbtest.Fixtures())
+	defer api.Close()
+	s := newMCPSession(t, api)
+
+	resp := s.call("initialize", map[string]interface{}{
+		"protocolVersion": mcpProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]string{"name": "test", "version": "0"},
+	})
+	var init struct {
+		ProtocolVersion string                     `json:"protocolVersion"`
+		Capabilities    map[string]json.RawMessage `json:"capabilities"`
+	}
+	if err := json.Unmarshal(resp.Result, &init); err != nil || init.ProtocolVersion != mcpProtocolVersion {
+		t.Fatalf("unexpected initialize result %s: %v", resp.Result, err)
+	}
+	if _, ok := init.Capabilities["tools"]; !ok {
+		t.Error("expected tools capability")
+	}
+
+	s.send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+	resp = s.call("tools/list", nil)
+	var list struct {
+		Tools []mcpTool `json:"tools"`
+	}
+	if err := json.Unmarshal(resp.Result, &list); err != nil {
+		t.Fatalf("invalid tools/list result: %v", err)
+	}
+	var names []string
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+		var schema map[string]interface{}
+		if err := json.Unmarshal(tool.InputSchema, &schema); err != nil || schema["type"] != "object" {
+			t.Errorf("invalid input schema for %s: %v", tool.Name, err)
+		}
+	}
+	if strings.Join(names, ",") != "search_parts,get_part,price_for_quantity" {
+		t.Errorf("unexpected tools %v", names)
+	}
+
+	if resp := s.call("ping", nil); resp.Error != nil || string(resp.Result) != "{}" {
+		t.Errorf("unexpected ping response %+v", resp)
+	}
+}
+
+// TestMCPTools tests each tool against the fake API.
+func TestMCPTools(t *testing.T) {
+	api := jlcpcbtest.NewServer(jlcpcbtest.Fixtures())
+	defer api.Close()
+	s := newMCPSession(t, api)
+
+	result := s.callTool("search_parts", map[string]interface{}{"query": "resistor", "package": "0402", "limit": 5})
+	var search searchJSON
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &search); err != nil {
+		t.Fatalf("invalid search result: %v", err)
+	}
+	if result.IsError || len(search.Parts) != 1 || search.Parts[0].Code != "C25744" {
+		t.Errorf("unexpected search result %+v", search)
+	}
+
+	result = s.callTool("get_part", map[string]string{"code": "C2040"})
+	var part partJSON
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &part); err != nil || part.MPN != "RP2040" {
+		t.Errorf("unexpected part %+v: %v", part, err)
+	}
+
+	result = s.callTool("price_for_quantity", map[string]interface{}{"code": "C25744", "quantity": 20000})
+	var quote struct {
+		UnitPrice     json.Number `json:"unit_price"`
+		ExtendedPrice json.Number `json:"extended_price"`
+		PriceBreak    int         `json:"price_break"`
+		MeetsMinOrder bool        `json:"meets_min_order"`
+		InStock       bool        `json:"in_stock"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &quote); err != nil {
+		t.Fatalf("invalid quote: %v", err)
+	}
+	if quote.UnitPrice != "0.0004" || quote.ExtendedPrice != "8" || quote.PriceBreak != 10000 || !quote.MeetsMinOrder || !quote.InStock {
+		t.Errorf("unexpected quote %s", result.Content[0].Text)
+	}
+}
+
+// TestMCPToolErrors tests that tool failures are reported as results.
+func TestMCPToolErrors(t *testing.T) {
+	api := jlcpcbtest.NewServer(jlcpcbtest.Fixtures())
+	defer api.Close()
+	s := newMCPSession(t, api)
+
+	for _, tt := range []struct {
+		tool string
+		args interface{}
+	}{
+		{"get_part", map[string]string{"code": "C999999"}},
+		{"get_part", map[string]string{}},
+		{"search_parts", map[string]string{"query": " "}},
+		{"price_for_quantity", map[string]interface{}{"code": "C25744", "quantity": 0}},
+	} {
+		if result := s.callTool(tt.tool, tt.args); !result.IsError {
+			t.Errorf("%s %v: expected error result, got %+v", tt.tool, tt.args, result)
+		}
+	}
+
+	if resp := s.call("tools/call", map[string]string{"name": "nope"}); resp.Error == nil || resp.Error.Code != rpcInvalidParams {
+		t.Errorf("expected invalid params for unknown tool, got %+v", resp)
+	}
+}
+
+// TestMCPProtocolErrors tests JSON-RPC error responses.
+func TestMCPProtocolErrors(t *testing.T) {
+	api := jlcpcbtest.NewServer(nil)
+	defer api.Close()
+	s := newMCPSession(t, api)
+
+	if resp := s.call("resources/list", nil); resp.Error == nil || resp.Error.Code != rpcMethodNotFound {
+		t.Errorf("expected method not found, got %+v", resp)
+	}
+
+	for line, code := range map[string]int{
+		`{not json`: rpcParseError,
+		`[{"jsonrpc":"2.0","id":1,"method":"ping"}]`: rpcInvalidRequest,
+		`{"jsonrpc":"1.0","id":7,"method":"ping"}`:   rpcInvalidRequest,
+	} {
+		s.send(line)
+		if resp := s.read(); resp.Error == nil || resp.Error.Code != code {
+			t.Errorf("%s: expected error %d, got %+v", line, code, resp)
+		}
+	}
+}
+
+// TestMCPCancel tests that a cancelled request gets no response.
+func TestMCPCancel(t *testing.T) {
+	api := jlcpcbtest.NewServer(jlcpcbtest.Fixtures())
+	defer api.Close()
+	api.SetLatency(2 * time.Second)
+	s := newMCPSession(t, api)
+
+	s.send(`{"jsonrpc":"2.0","id":"slow","method":"tools/call","params":{"name":"search_parts","arguments":{"query":"resistor"}}}`)
+	s.send(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"slow"}}`)
+	if resp := s.call("ping", nil); resp.Error != nil {
+		t.Fatalf("ping failed: %+v", resp.Error)
+	}
+
+	s.in.Close()
+	for s.out.Scan() {
+		t.Errorf("unexpected response after cancellation: %s", s.out.Text())
+	}
+	if err := <-s.done; err != nil {
+		t.Errorf("serve failed: %v", err)
+	}
+	s.done <- nil
+}
+
+// TestMCPDuplicateID tests that a repeated in-flight ID is rejected without
+// disturbing the first request.
+func TestMCPDuplicateID(t *testing.T) {
+	api := jlcpcbtest.NewServer(jlcpcbtest.Fixtures())
+	defer api.Close()
+	api.SetLatency(200 * time.Millisecond)
+	s := newMCPSession(t, api)
+
+	line := `{"jsonrpc":"2.0","id":"dup","method":"tools/call","params":{"name":"get_part","arguments":{"code":"C25744"}}}`
+	s.send(line)
+	s.send(line)
+
+	resp := s.read()
+	if string(resp.ID) != `"dup"` || resp.Error == nil || resp.Error.Code != rpcInvalidRequest {
+		t.Fatalf("expected the duplicate to be rejected, got %+v", resp)
+	}
+	resp = s.read()
+	if string(resp.ID) != `"dup"` || resp.Error != nil {
+		t.Fatalf("expected the first request to complete, got %+v", resp)
+	}
+	var result mcpToolResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil || result.IsError {
+		t.Errorf("expected a tool result, got %s", resp.Result)
+	}
+
+	// The ID is free again once the first request is done.
+	s.send(strings.Replace(line, "C25744", "C1525", 1))
+	if resp := s.read(); resp.Error != nil {
+		t.Errorf("expected the reused ID to be accepted, got %+v", resp.Error)
+	}
+}
+
+// TestMCPOversizedMessage tests that a message over the size limit is
+// skipped without ending the session.
+func TestMCPOversizedMessage(t *testing.T) {
+	api := jlcpcbtest.NewServer(nil)
+	defer api.Close()
+	s := newMCPSession(t, api)
+
+	padding := strings.Repeat("x", maxBodySize)
+	s.send(`{"jsonrpc":"2.0","id":9,"method":"ping","params":{"padding":"` + padding + `"}}`)
+	if resp := s.read(); string(resp.ID) != "9" || resp.Error == nil || resp.Error.Code != rpcParseError {
+		t.Errorf("expected a parse error for request 9, got %+v", resp)
+	}
+
+	// Without a recoverable ID the message is dropped silently.
+	s.send(`{"jsonrpc":"2.0","method":"ping","params":{"padding":"` + padding + `"},"id":10}`)
+	if resp := s.call("ping", nil); resp.Error != nil {
+		t.Errorf("ping after oversized messages failed: %+v", resp.Error)
+	}
+}
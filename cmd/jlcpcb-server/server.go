@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+const (
+	maxBatchCodes    = 100     // Most part codes accepted by /parts:batch
+	batchConcurrency = 4       // Concurrent lookups per batch; the client's limiter still applies
+	maxBodySize      = 1 << 20 // Largest accepted request body
+)
+
+// server serves the HTTP/JSON API on top of a PartsAPI.
+type server struct {
+	api     jlcpcb.PartsAPI
+	logger  *slog.Logger
+	timeout time.Duration
+	mux     *http.ServeMux
+	metrics *expvar.Map
+}
+
+// serverMetrics holds the request counters published under "server". It is
+// shared because expvar names can only be published once per process.
+var serverMetrics = expvar.NewMap("server")
+
+// newServer returns the API handler. A zero timeout means no limit.
+func newServer(api jlcpcb.PartsAPI, logger *slog.Logger, timeout time.Duration) *server {
+	s := &server{
+		api:     api,
+		logger:  logger,
+		timeout: timeout,
+		mux:     http.NewServeMux(),
+		metrics: serverMetrics,
+	}
+	s.mux.HandleFunc("/search", s.handleSearch)
+	s.mux.HandleFunc("/parts/", s.handlePart)
+	s.mux.HandleFunc("/parts:batch", s.handleBatch)
+	s.mux.HandleFunc("/healthz", s.handleHealth)
+	s.mux.Handle("/metrics", expvar.Handler())
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "not_found", "no such endpoint")
+	})
+	return s
+}
+
+// ServeHTTP implements http.Handler, applying the timeout and counting
+// responses by status.
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.timeout > 0 {
+		ctx, cancel := context.WithTimeout(r.Context(), s.timeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+	}
+
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	s.mux.ServeHTTP(rec, r)
+
+	s.metrics.Add("requests", 1)
+	s.metrics.Add("status_"+strconv.Itoa(rec.status), 1)
+	s.logger.Debug("served request",
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.Int("status", rec.status),
+		slog.Duration("duration", time.Since(start)))
+}
+
+// handleSearch serves GET /search.
+func (s *server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	req, err := parseSearchRequest(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", err.Error())
+		return
+	}
+
+	resp, err := s.api.KeywordSearch(r.Context(), req)
+	if err != nil {
+		s.writeAPIError(w, r, err)
+		return
+	}
+
+	parts := make([]partJSON, len(resp.Products))
+	for i := range resp.Products {
+		parts[i] = newPartJSON(&resp.Products[i])
+	}
+	page := searchJSON{Total: resp.TotalCount, Page: resp.PageNumber, PageSize: resp.PageSize, Parts: parts}
+	if page.Page == 0 {
+		page.Page = req.CurrentPage
+	}
+	if page.PageSize == 0 {
+		page.PageSize = req.PageSize
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// parseSearchRequest builds a search request from query parameters.
+func parseSearchRequest(q url.Values) (jlcpcb.SearchRequest, error) {
+	req := jlcpcb.SearchRequest{
+		Keyword:       strings.TrimSpace(q.Get("q")),
+		Packages:      q["package"],
+		Brands:        q["brand"],
+		ComponentType: q.Get("type"),
+		PresaleType:   q.Get("presale"),
+		SortBy:        q.Get("sort"),
+	}
+	if req.Keyword == "" {
+		return req, errors.New("missing q parameter")
+	}
+
+	var err error
+	if req.CurrentPage, err = intParam(q, "page", 1); err != nil {
+		return req, err
+	}
+	if req.PageSize, err = intParam(q, "size", 0); err != nil {
+		return req, err
+	}
+	if v := q.Get("inStock"); v != "" {
+		inStock, err := strconv.ParseBool(v)
+		if err != nil {
+			return req, errors.New("invalid inStock parameter")
+		}
+		req.IsAvailable, req.StockOnly = inStock, inStock
+	}
+	for _, attr := range q["attr"] {
+		name, value, ok := strings.Cut(attr, ":")
+		if !ok {
+			return req, errors.New("invalid attr parameter, want name:value")
+		}
+		req.Attributes = append(req.Attributes, jlcpcb.FilterAttribute{Name: name, Value: value})
+	}
+	return req, nil
+}
+
+// intParam parses an optional positive integer query parameter.
+func intParam(q url.Values, name string, def int) (int, error) {
+	v := q.Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 0, errors.New("invalid " + name + " parameter")
+	}
+	return n, nil
+}
+
+// handlePart serves GET /parts/{code}.
+func (s *server) handlePart(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	code := strings.TrimPrefix(r.URL.Path, "/parts/")
+	if code == "" || strings.Contains(code, "/") {
+		writeError(w, http.StatusNotFound, "not_found", "no such endpoint")
+		return
+	}
+
+	product, err := s.api.GetProductDetails(r.Context(), code)
+	if err != nil {
+		s.writeAPIError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newPartJSON(product))
+}
+
+// batchRequest is the body of POST /parts:batch.
+type batchRequest struct {
+	Codes []string `json:"codes"`
+}
+
+// handleBatch serves POST /parts:batch. Results are returned in request
+// order, each with either a part or an error, so one missing part does not
+// fail the batch.
+func (s *server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	var req batchRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_input", "invalid JSON body: "+err.Error())
+		return
+	}
+	if len(req.Codes) == 0 {
+		writeError(w, http.StatusBadRequest, "invalid_input", "codes must not be empty")
+		return
+	}
+	if len(req.Codes) > maxBatchCodes {
+		writeError(w, http.StatusBadRequest, "invalid_input", "at most "+strconv.Itoa(maxBatchCodes)+" codes per batch")
+		return
+	}
+
+	results := make([]batchResultJSON, len(req.Codes))
+	sem := make(chan struct{}, batchConcurrency)
+	var wg sync.WaitGroup
+	for i, code := range req.Codes {
+		wg.Add(1)
+		go func(i int, code string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i].Code = code
+			product, err := s.api.GetProductDetails(r.Context(), code)
+			if err != nil {
+				_, e := apiError(err)
+				results[i].Error = &e
+				return
+			}
+			part := newPartJSON(product)
+			results[i].Part = &part
+		}(i, code)
+	}
+	wg.Wait()
+
+	writeJSON(w, http.StatusOK, batchJSON{Results: results})
+}
+
+// handleHealth serves GET /healthz.
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// writeAPIError writes the response for an error from the PartsAPI.
+func (s *server) writeAPIError(w http.ResponseWriter, r *http.Request, err error) {
+	status, e := apiError(err)
+	if status >= http.StatusInternalServerError {
+		s.logger.Warn("upstream request failed", slog.String("path", r.URL.Path), slog.Any("error", err))
+	}
+	if status == http.StatusTooManyRequests {
+		w.Header().Set("Retry-After", "5")
+	}
+	writeJSON(w, status, errorJSON{Error: e})
+}
+
+// apiError maps a PartsAPI error to an HTTP status and error body.
+func apiError(err error) (int, errorDetailJSON) {
+	var notFound jlcpcb.ErrProductNotFound
+	var invalid jlcpcb.ErrInvalidInput
+	var limited jlcpcb.ErrRateLimited
+	switch {
+	case errors.As(err, &notFound):
+		return http.StatusNotFound, errorDetailJSON{Code: "not_found", Message: err.Error()}
+	case errors.As(err, &invalid):
+		return http.StatusBadRequest, errorDetailJSON{Code: "invalid_input", Message: err.Error()}
+	case errors.As(err, &limited):
+		return http.StatusTooManyRequests, errorDetailJSON{Code: "rate_limited", Message: err.Error()}
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, errorDetailJSON{Code: "timeout", Message: err.Error()}
+	default:
+		return http.StatusBadGateway, errorDetailJSON{Code: "upstream_error", Message: err.Error()}
+	}
+}
+
+// allowMethod writes a 405 response unless r uses method.
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method || (method == http.MethodGet && r.Method == http.MethodHead) {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", "use "+method)
+	return false
+}
+
+// writeError writes an error response.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, errorJSON{Error: errorDetailJSON{Code: code, Message: message}})
+}
+
+// writeJSON writes v as an indented JSON response.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// statusRecorder captures the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (sr *statusRecorder) WriteHeader(status int) {
+	sr.status = status
+	sr.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+	"github.com/PatrickWalther/go-jlcpcb-parts/jlcpcbtest"
+)
+
+// newTestServer starts a server backed by the fixture catalog.
+func newTestServer(t *testing.T, api jlcpcb.PartsAPI) *httptest.Server {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	server := httptest.NewServer(newServer(api, logger, time.Second))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// getJSON performs a request and decodes the JSON response into v.
+func getJSON(t *testing.T, method, url, body string, v interface{}) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("expected JSON content type, got %q", ct)
+	}
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+	}
+	return resp
+}
+
+// TestSearch tests the search endpoint and its filters.
+func TestSearch(t *testing.T) {
+	server := newTestServer(t, jlcpcbtest.NewFake(jlcpcbtest.Fixtures()...))
+
+	var result searchJSON
+	resp := getJSON(t, http.MethodGet, server.URL+"/search?q=resistor&package=0402&size=10", "", &result)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if result.Total != 1 || len(result.Parts) != 1 || result.Parts[0].Code != "C25744" {
+		t.Fatalf("unexpected search result: %+v", result)
+	}
+	if result.Page != 1 || result.PageSize != 10 {
+		t.Errorf("unexpected paging: page %d size %d", result.Page, result.PageSize)
+	}
+
+	part := result.Parts[0]
+	if part.LibraryType != "basic" || part.Package != "0402" || len(part.Prices) == 0 || len(part.Attributes) == 0 {
+		t.Errorf("unexpected part: %+v", part)
+	}
+
+	var errResp errorJSON
+	resp = getJSON(t, http.MethodGet, server.URL+"/search", "", &errResp)
+	if resp.StatusCode != http.StatusBadRequest || errResp.Error.Code != "invalid_input" {
+		t.Errorf("expected invalid_input for missing keyword, got %d %+v", resp.StatusCode, errResp)
+	}
+
+	resp = getJSON(t, http.MethodGet, server.URL+"/search?q=x&page=0", "", &errResp)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid page, got %d", resp.StatusCode)
+	}
+}
+
+// TestPart tests single part lookups and error mapping.
+func TestPart(t *testing.T) {
+	server := newTestServer(t, jlcpcbtest.NewFake(jlcpcbtest.Fixtures()...))
+
+	var part partJSON
+	resp := getJSON(t, http.MethodGet, server.URL+"/parts/C2040", "", &part)
+	if resp.StatusCode != http.StatusOK || part.Code != "C2040" || part.MPN != "RP2040" {
+		t.Fatalf("unexpected part %d: %+v", resp.StatusCode, part)
+	}
+	if part.LibraryType != "extended" || !part.Preferred || part.ImageURL == "" {
+		t.Errorf("unexpected part fields: %+v", part)
+	}
+	last := part.Prices[len(part.Prices)-1]
+	if last.MaxQuantity != nil {
+		t.Errorf("expected unbounded last tier, got max %d", *last.MaxQuantity)
+	}
+
+	var errResp errorJSON
+	resp = getJSON(t, http.MethodGet, server.URL+"/parts/C999999", "", &errResp)
+	if resp.StatusCode != http.StatusNotFound || errResp.Error.Code != "not_found" {
+		t.Errorf("expected not_found, got %d %+v", resp.StatusCode, errResp)
+	}
+
+	resp = getJSON(t, http.MethodPost, server.URL+"/parts/C2040", "", &errResp)
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != http.MethodGet {
+		t.Errorf("expected 405, got %d", resp.StatusCode)
+	}
+}
+
+// TestPartClient tests not_found mapping with the real client against the
+// fake JLCPCB server.
+func TestPartClient(t *testing.T) {
+	api := jlcpcbtest.NewServer(jlcpcbtest.Fixtures())
+	defer api.Close()
+	client := jlcpcb.NewClient(jlcpcb.WithBaseURL(api.URL), jlcpcb.WithRateLimit(1000))
+	server := newTestServer(t, client)
+
+	var part partJSON
+	resp := getJSON(t, http.MethodGet, server.URL+"/parts/C2040", "", &part)
+	if resp.StatusCode != http.StatusOK || part.Code != "C2040" {
+		t.Fatalf("unexpected part %d: %+v", resp.StatusCode, part)
+	}
+
+	for _, code := range []string{"C999999", "0402"} {
+		var errResp errorJSON
+		resp = getJSON(t, http.MethodGet, server.URL+"/parts/"+code, "", &errResp)
+		if resp.StatusCode != http.StatusNotFound || errResp.Error.Code != "not_found" {
+			t.Errorf("%s: expected not_found, got %d %+v", code, resp.StatusCode, errResp)
+		}
+	}
+
+	var result batchJSON
+	getJSON(t, http.MethodPost, server.URL+"/parts:batch", `{"codes":["C25744","C999999"]}`, &result)
+	if len(result.Results) != 2 || result.Results[0].Part == nil || result.Results[1].Error == nil || result.Results[1].Error.Code != "not_found" {
+		t.Errorf("expected a part and a not_found result, got %+v", result.Results)
+	}
+}
+
+// TestPartErrors tests status codes for upstream failures.
+func TestPartErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{jlcpcb.ErrRateLimited{}, http.StatusTooManyRequests, "rate_limited"},
+		{jlcpcb.ErrInvalidInput{Message: "bad"}, http.StatusBadRequest, "invalid_input"},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout, "timeout"},
+		{io.ErrUnexpectedEOF, http.StatusBadGateway, "upstream_error"},
+	}
+
+	for _, tt := range tests {
+		fake := jlcpcbtest.NewFake()
+		fake.SetError(tt.err)
+		server := newTestServer(t, fake)
+
+		var errResp errorJSON
+		resp := getJSON(t, http.MethodGet, server.URL+"/parts/C1", "", &errResp)
+		if resp.StatusCode != tt.status || errResp.Error.Code != tt.code {
+			t.Errorf("%v: expected %d %s, got %d %+v", tt.err, tt.status, tt.code, resp.StatusCode, errResp)
+		}
+	}
+}
+
+// TestBatch tests batch lookups with partial failures.
+func TestBatch(t *testing.T) {
+	server := newTestServer(t, jlcpcbtest.NewFake(jlcpcbtest.Fixtures()...))
+
+	var result batchJSON
+	resp := getJSON(t, http.MethodPost, server.URL+"/parts:batch", `{"codes":["C25744","C999999","C1525"]}`, &result)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if len(result.Results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(result.Results))
+	}
+	for i, code := range []string{"C25744", "C999999", "C1525"} {
+		if result.Results[i].Code != code {
+			t.Errorf("expected result %d for %s, got %s", i, code, result.Results[i].Code)
+		}
+	}
+	if r := result.Results[0]; r.Part == nil || r.Error != nil {
+		t.Errorf("unexpected result %+v", r)
+	}
+	if r := result.Results[1]; r.Part != nil || r.Error == nil || r.Error.Code != "not_found" {
+		t.Errorf("unexpected result %+v", r)
+	}
+
+	for _, body := range []string{`{"codes":[]}`, `not json`, `{"codes":["C1"` + strings.Repeat(`,"C1"`, maxBatchCodes) + `]}`} {
+		var errResp errorJSON
+		resp := getJSON(t, http.MethodPost, server.URL+"/parts:batch", body, &errResp)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected 400 for %.20q, got %d", body, resp.StatusCode)
+		}
+	}
+}
+
+// TestHealthAndMetrics tests the operational endpoints.
+func TestHealthAndMetrics(t *testing.T) {
+	server := newTestServer(t, jlcpcbtest.NewFake())
+
+	var health map[string]string
+	resp := getJSON(t, http.MethodGet, server.URL+"/healthz", "", &health)
+	if resp.StatusCode != http.StatusOK || health["status"] != "ok" {
+		t.Errorf("unexpected health response %d %v", resp.StatusCode, health)
+	}
+
+	var metrics map[string]json.RawMessage
+	getJSON(t, http.MethodGet, server.URL+"/metrics", "", &metrics)
+	if _, ok := metrics["server"]; !ok {
+		t.Errorf("expected server metrics, got keys %v", metrics)
+	}
+
+	var errResp errorJSON
+	resp = getJSON(t, http.MethodGet, server.URL+"/nope", "", &errResp)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown path, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,169 @@
+package cpl
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strings"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+// Correction adjusts a placement to match the orientation and origin of a
+// part on JLCPCB's reels.
+type Correction struct {
+	Rotation float64 `json:"rotation"`          // Degrees added counter-clockwise on the top side
+	OffsetX  float64 `json:"offsetX,omitempty"` // X offset in mm, in the part's own frame
+	OffsetY  float64 `json:"offsetY,omitempty"` // Y offset in mm, in the part's own frame
+}
+
+// IsZero reports whether the correction changes nothing.
+func (c Correction) IsZero() bool {
+	return c == Correction{}
+}
+
+// Apply returns p corrected by c. On the bottom side the board is viewed
+// mirrored, so the rotation is subtracted and the X offset is negated. The
+// offset is rotated by the placement's original rotation, so it follows the
+// part.
+func (c Correction) Apply(p Placement) Placement {
+	dx, dy := c.OffsetX, c.OffsetY
+	rotation := c.Rotation
+	if p.Layer == Bottom {
+		dx, rotation = -dx, -rotation
+	}
+
+	theta := p.Rotation * math.Pi / 180
+	sin, cos := math.Sincos(theta)
+	p.X += dx*cos - dy*sin
+	p.Y += dx*sin + dy*cos
+	p.Rotation = normalizeRotation(p.Rotation + rotation)
+	return p
+}
+
+// CorrectionRule is one entry of a correction database. A rule matches
+// either a part code exactly or a package by regular expression.
+type CorrectionRule struct {
+	Part    string `json:"part,omitempty"`    // JLCPCB part code, e.g. "C2040"
+	Package string `json:"package,omitempty"` // Regular expression matched against ComponentSpecificationEn
+	Correction
+}
+
+// CorrectionDB looks up the correction for a part. Rules keyed on a part
+// code take precedence over package rules; package rules are tried in the
+// order they were added.
+type CorrectionDB struct {
+	parts    map[string]Correction
+	packages []packageRule
+}
+
+// packageRule is a compiled package rule.
+type packageRule struct {
+	pattern    *regexp.Regexp
+	correction Correction
+}
+
+// NewCorrectionDB creates a database from rules.
+func NewCorrectionDB(rules ...CorrectionRule) (*CorrectionDB, error) {
+	db := &CorrectionDB{parts: make(map[string]Correction)}
+	if err := db.Add(rules...); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// Add adds rules to the database. A part rule replaces an earlier rule for
+// the same part; package rules are appended.
+func (db *CorrectionDB) Add(rules ...CorrectionRule) error {
+	for _, rule := range rules {
+		switch {
+		case rule.Part != "" && rule.Package != "":
+			return fmt.Errorf("correction rule sets both part %q and package %q", rule.Part, rule.Package)
+		case rule.Part != "":
+			db.parts[strings.ToUpper(strings.TrimSpace(rule.Part))] = rule.Correction
+		case rule.Package != "":
+			pattern, err := regexp.Compile("(?i)" + rule.Package)
+			if err != nil {
+				return fmt.Errorf("invalid package pattern %q: %w", rule.Package, err)
+			}
+			db.packages = append(db.packages, packageRule{pattern: pattern, correction: rule.Correction})
+		default:
+			return fmt.Errorf("correction rule sets neither part nor package")
+		}
+	}
+	return nil
+}
+
+// Lookup returns the correction for a part code and package, and whether
+// any rule matched. Either may be empty.
+func (db *CorrectionDB) Lookup(part, pkg string) (Correction, bool) {
+	if db == nil {
+		return Correction{}, false
+	}
+	if c, ok := db.parts[strings.ToUpper(strings.TrimSpace(part))]; ok && part != "" {
+		return c, true
+	}
+	if pkg == "" {
+		return Correction{}, false
+	}
+	for _, rule := range db.packages {
+		if rule.pattern.MatchString(pkg) {
+			return rule.correction, true
+		}
+	}
+	return Correction{}, false
+}
+
+// LookupProduct returns the correction for a product, by its part code and
+// ComponentSpecificationEn.
+func (db *CorrectionDB) LookupProduct(p *jlcpcb.Product) (Correction, bool) {
+	return db.Lookup(p.ComponentCode, p.ComponentSpecificationEn)
+}
+
+// ReadCorrections reads rules from a JSON array such as
+//
+//	[
+//	  {"package": "^SOT-23", "rotation": 180},
+//	  {"part": "C2040", "rotation": 90, "offsetX": 0.1}
+//	]
+func ReadCorrections(r io.Reader) ([]CorrectionRule, error) {
+	var rules []CorrectionRule
+	if err := json.NewDecoder(r).Decode(&rules); err != nil {
+		return nil, fmt.Errorf("failed to parse corrections: %w", err)
+	}
+	return rules, nil
+}
+
+// LoadCorrections reads a JSON correction file into a new database that
+// starts from DefaultCorrections. Rules in the file take precedence.
+func LoadCorrections(path string) (*CorrectionDB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open corrections: %w", err)
+	}
+	defer f.Close()
+
+	rules, err := ReadCorrections(f)
+	if err != nil {
+		return nil, err
+	}
+	return NewCorrectionDB(append(rules, DefaultCorrections()...)...)
+}
+
+// DefaultCorrections returns package rules commonly needed to match
+// JLCPCB's reel orientation for footprints drawn to IPC-7351 conventions.
+// They are a starting point; always check the placement preview when
+// ordering.
+func DefaultCorrections() []CorrectionRule {
+	return []CorrectionRule{
+		{Package: `^SOT-23`, Correction: Correction{Rotation: 180}},
+		{Package: `^SOT-89`, Correction: Correction{Rotation: 180}},
+		{Package: `^SOT-223`, Correction: Correction{Rotation: 180}},
+		{Package: `^TO-252`, Correction: Correction{Rotation: 180}},
+		{Package: `^(SOIC|SOP|SSOP|TSSOP|MSOP|VSSOP)-`, Correction: Correction{Rotation: 270}},
+		{Package: `^(QFN|DFN|LQFP|TQFP|QFP)-`, Correction: Correction{Rotation: 270}},
+	}
+}
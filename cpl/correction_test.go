@@ -0,0 +1,97 @@
+package cpl
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestCorrectionApply tests rotation and offset correction on both sides.
+func TestCorrectionApply(t *testing.T) {
+	c := Correction{Rotation: 90, OffsetX: 1}
+
+	top := c.Apply(Placement{X: 10, Y: 10, Rotation: 270, Layer: Top})
+	if top.Rotation != 0 {
+		t.Errorf("expected rotation 0, got %v", top.Rotation)
+	}
+	// The offset follows the part's original rotation of 270 degrees.
+	if math.Abs(top.X-10) > 1e-9 || math.Abs(top.Y-9) > 1e-9 {
+		t.Errorf("expected offset rotated with the part, got %v, %v", top.X, top.Y)
+	}
+
+	bottom := c.Apply(Placement{X: 10, Y: 10, Rotation: 0, Layer: Bottom})
+	if bottom.Rotation != 270 || math.Abs(bottom.X-9) > 1e-9 || bottom.Y != 10 {
+		t.Errorf("expected mirrored correction on bottom, got %+v", bottom)
+	}
+}
+
+// TestCorrectionDBLookup tests part precedence and package rule order.
+func TestCorrectionDBLookup(t *testing.T) {
+	db, err := NewCorrectionDB(
+		CorrectionRule{Package: `^SOT-23-5`, Correction: Correction{Rotation: 90}},
+		CorrectionRule{Package: `^SOT-23`, Correction: Correction{Rotation: 180}},
+		CorrectionRule{Part: "c6186", Correction: Correction{Rotation: 270}},
+	)
+	if err != nil {
+		t.Fatalf("NewCorrectionDB failed: %v", err)
+	}
+
+	tests := []struct {
+		part, pkg string
+		rotation  float64
+		ok        bool
+	}{
+		{"C1", "SOT-23-5", 90, true},
+		{"C1", "sot-23", 180, true},
+		{"C6186", "SOT-23", 270, true},
+		{"C6186", "", 270, true},
+		{"C1", "0402", 0, false},
+		{"", "", 0, false},
+	}
+	for _, tt := range tests {
+		c, ok := db.Lookup(tt.part, tt.pkg)
+		if ok != tt.ok || c.Rotation != tt.rotation {
+			t.Errorf("Lookup(%q, %q) = %+v, %v; want rotation %v, %v", tt.part, tt.pkg, c, ok, tt.rotation, tt.ok)
+		}
+	}
+
+	var nilDB *CorrectionDB
+	if _, ok := nilDB.Lookup("C1", "SOT-23"); ok {
+		t.Error("expected nil database to match nothing")
+	}
+}
+
+// TestCorrectionDBInvalid tests rule validation.
+func TestCorrectionDBInvalid(t *testing.T) {
+	for _, rule := range []CorrectionRule{
+		{},
+		{Part: "C1", Package: "0402"},
+		{Package: "("},
+	} {
+		if _, err := NewCorrectionDB(rule); err == nil {
+			t.Errorf("expected error for rule %+v", rule)
+		}
+	}
+}
+
+// TestReadCorrections tests the JSON rule format.
+func TestReadCorrections(t *testing.T) {
+	rules, err := ReadCorrections(strings.NewReader(`[
+		{"package": "^SOT-23", "rotation": 180},
+		{"part": "C2040", "rotation": 90, "offsetX": 0.1, "offsetY": -0.2}
+	]`))
+	if err != nil {
+		t.Fatalf("ReadCorrections failed: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(rules))
+	}
+	want := CorrectionRule{Part: "C2040", Correction: Correction{Rotation: 90, OffsetX: 0.1, OffsetY: -0.2}}
+	if rules[1] != want {
+		t.Errorf("unexpected rule %+v", rules[1])
+	}
+
+	if _, err := NewCorrectionDB(DefaultCorrections()...); err != nil {
+		t.Errorf("expected default corrections to compile: %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package cpl
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+// BOMLine is one row of a JLCPCB assembly BOM.
+type BOMLine struct {
+	Comment     string   // Value or description
+	Designators []string // Reference designators placed with this part
+	Footprint   string   // Footprint name
+	Part        string   // JLCPCB/LCSC part code, e.g. "C25744"
+}
+
+// bomColumns lists the accepted BOM header names, normalized as for
+// placement files.
+var bomColumns = map[string][]string{
+	"comment":    {"comment", "value", "val"},
+	"designator": {"designator", "designators", "reference", "references", "ref"},
+	"footprint":  {"footprint", "package"},
+	"part":       {"lcscpart", "lcscpartnumber", "lcsc", "jlcpcbpart", "jlcpcbpartnumber", "partnumber"},
+}
+
+// ReadBOM reads a JLCPCB assembly BOM in CSV format. The designator and
+// part columns are required; designators may be separated by commas or
+// spaces.
+func ReadBOM(r io.Reader) ([]BOMLine, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse BOM header: %w", err)
+	}
+	index := make(map[string]int)
+	for i, name := range header {
+		index[normalizeHeader(name)] = i
+	}
+	cols := make(map[string]int)
+	for col, aliases := range bomColumns {
+		for _, alias := range aliases {
+			if i, ok := index[alias]; ok {
+				cols[col] = i
+				break
+			}
+		}
+	}
+	for _, required := range []string{"designator", "part"} {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("failed to parse BOM: missing %s column", required)
+		}
+	}
+
+	var lines []BOMLine
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse BOM: %w", err)
+		}
+		get := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		designators := strings.FieldsFunc(get("designator"), func(r rune) bool {
+			return r == ',' || r == ' ' || r == ';'
+		})
+		if len(designators) == 0 {
+			continue
+		}
+		lines = append(lines, BOMLine{
+			Comment:     get("comment"),
+			Designators: designators,
+			Footprint:   get("footprint"),
+			Part:        strings.ToUpper(get("part")),
+		})
+	}
+	return lines, nil
+}
+
+// Entry is a placement joined with its BOM line and part.
+type Entry struct {
+	Placement  Placement       // Corrected placement
+	Original   Placement       // Placement as read from the EDA file
+	Part       string          // Part code from the BOM
+	Product    *jlcpcb.Product // Resolved part; nil if the lookup failed
+	Correction Correction      // Correction applied
+	Corrected  bool            // Whether a correction rule matched
+	Err        error           // Lookup error, if any
+}
+
+// Join matches placements to BOM lines by designator, looks up each part
+// once through api, and applies the correction db finds for the part.
+// Placements without a BOM line are not assembled and are left out. Lookup
+// failures are reported per entry; rules keyed on the part code still
+// apply. db may be nil. Join itself only fails if ctx is done.
+func Join(ctx context.Context, api jlcpcb.PartsAPI, placements []Placement, bom []BOMLine, db *CorrectionDB) ([]Entry, error) {
+	parts := make(map[string]string)
+	for _, line := range bom {
+		for _, d := range line.Designators {
+			parts[d] = line.Part
+		}
+	}
+
+	type lookup struct {
+		product *jlcpcb.Product
+		err     error
+	}
+	lookups := make(map[string]lookup)
+
+	var entries []Entry
+	for _, p := range placements {
+		part, ok := parts[p.Designator]
+		if !ok {
+			continue
+		}
+		entry := Entry{Placement: p, Original: p, Part: part}
+
+		if part != "" {
+			l, ok := lookups[part]
+			if !ok {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				l.product, l.err = api.GetProductDetails(ctx, part)
+				lookups[part] = l
+			}
+			entry.Product, entry.Err = l.product, l.err
+		}
+
+		pkg := ""
+		if entry.Product != nil {
+			pkg = entry.Product.ComponentSpecificationEn
+		}
+		if c, ok := db.Lookup(part, pkg); ok {
+			entry.Correction, entry.Corrected = c, true
+			entry.Placement = c.Apply(p)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Placements returns the corrected placements of entries, for WriteCPL.
+func Placements(entries []Entry) []Placement {
+	placements := make([]Placement, len(entries))
+	for i, e := range entries {
+		placements[i] = e.Placement
+	}
+	return placements
+}
@@ -0,0 +1,96 @@
+package cpl
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+	"github.com/PatrickWalther/go-jlcpcb-parts/jlcpcbtest"
+)
+
+// TestReadBOM tests parsing of a JLCPCB BOM.
+func TestReadBOM(t *testing.T) {
+	input := "Comment,Designator,Footprint,LCSC Part #\n" +
+		"10k,\"R1, R2\",R_0402_1005Metric,c25744\n" +
+		"AMS1117-3.3,U1,SOT-223-3_TabPin2,C6186\n"
+
+	lines, err := ReadBOM(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadBOM failed: %v", err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	if l := lines[0]; l.Part != "C25744" || len(l.Designators) != 2 || l.Designators[1] != "R2" || l.Comment != "10k" {
+		t.Errorf("unexpected line %+v", l)
+	}
+
+	if _, err := ReadBOM(strings.NewReader("Comment,Designator\n10k,R1\n")); err == nil {
+		t.Error("expected error for BOM without part column")
+	}
+}
+
+// TestJoin tests joining placements with BOM lines and corrections.
+func TestJoin(t *testing.T) {
+	fake := jlcpcbtest.NewFake(jlcpcbtest.Fixtures()...)
+	placements := []Placement{
+		{Designator: "R1", X: 1, Y: 1, Rotation: 0, Layer: Top},
+		{Designator: "R2", X: 2, Y: 1, Rotation: 90, Layer: Top},
+		{Designator: "U1", X: 10, Y: 10, Rotation: 90, Layer: Top},
+		{Designator: "U2", X: 20, Y: 10, Rotation: 0, Layer: Top},
+		{Designator: "H1", X: 0, Y: 0, Layer: Top},
+	}
+	bom := []BOMLine{
+		{Designators: []string{"R1", "R2"}, Part: "C25744"},
+		{Designators: []string{"U1"}, Part: "C6186"},
+		{Designators: []string{"U2"}, Part: "C999999"},
+	}
+	db, err := NewCorrectionDB(
+		CorrectionRule{Package: `^SOT-223`, Correction: Correction{Rotation: 180}},
+		CorrectionRule{Part: "C999999", Correction: Correction{Rotation: 90}},
+	)
+	if err != nil {
+		t.Fatalf("NewCorrectionDB failed: %v", err)
+	}
+
+	entries, err := Join(context.Background(), fake, placements, bom, db)
+	if err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("expected placements without a BOM line to be dropped, got %d entries", len(entries))
+	}
+	if lookups := fake.Lookups(); len(lookups) != 3 {
+		t.Errorf("expected one lookup per part, got %v", lookups)
+	}
+
+	if e := entries[0]; e.Product == nil || e.Corrected || e.Placement != e.Original {
+		t.Errorf("expected R1 resolved without correction, got %+v", e)
+	}
+	if e := entries[2]; !e.Corrected || e.Placement.Rotation != 270 || e.Original.Rotation != 90 {
+		t.Errorf("expected package correction for U1, got %+v", e)
+	}
+
+	var notFound jlcpcb.ErrProductNotFound
+	if e := entries[3]; !errors.As(e.Err, &notFound) || !e.Corrected || e.Placement.Rotation != 90 {
+		t.Errorf("expected part correction despite lookup failure for U2, got %+v", e)
+	}
+
+	got := Placements(entries)
+	if len(got) != 4 || got[2].Rotation != 270 {
+		t.Errorf("unexpected corrected placements %+v", got)
+	}
+}
+
+// TestJoinCancelled tests that a done context stops the join.
+func TestJoinCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Join(ctx, jlcpcbtest.NewFake(), []Placement{{Designator: "R1"}}, []BOMLine{{Designators: []string{"R1"}, Part: "C1"}}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,337 @@
+// Package cpl converts EDA pick-and-place files into JLCPCB's component
+// placement list (CPL) format, joins placements with BOM lines resolved
+// through a jlcpcb.PartsAPI, and corrects rotations and offsets that
+// disagree with JLCPCB's reel orientation.
+package cpl
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Layer is the board side a part is placed on.
+type Layer string
+
+// Board sides, spelled as JLCPCB expects them in a CPL file.
+const (
+	Top    Layer = "Top"
+	Bottom Layer = "Bottom"
+)
+
+// Placement is the position of one part on the board.
+type Placement struct {
+	Designator string  // Reference designator, e.g. "R1"
+	Value      string  // Value or comment, if the source has one
+	Footprint  string  // Footprint or package name, if the source has one
+	X          float64 // Centre X in mm
+	Y          float64 // Centre Y in mm
+	Rotation   float64 // Rotation in degrees, counter-clockwise, in [0, 360)
+	Layer      Layer   // Board side
+}
+
+// ReadKiCad reads a footprint position file exported by KiCad, in either
+// the ASCII ("Ref Val Package PosX PosY Rot Side") or the CSV format.
+// Positions must be in millimetres.
+func ReadKiCad(r io.Reader) ([]Placement, error) {
+	var placements []Placement
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "Ref,") {
+			continue
+		}
+
+		var fields []string
+		if strings.Contains(text, ",") {
+			record, err := csv.NewReader(strings.NewReader(text)).Read()
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse positions line %d: %w", line, err)
+			}
+			fields = record
+		} else {
+			fields = strings.Fields(text)
+		}
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("failed to parse positions line %d: expected 7 columns, got %d", line, len(fields))
+		}
+
+		p, err := newPlacement(fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6], "mm")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse positions line %d: %w", line, err)
+		}
+		placements = append(placements, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read positions: %w", err)
+	}
+	return placements, nil
+}
+
+// ReadEagle reads an Eagle mount file (.mnt or .mnb) as written by
+// mountsmd.ulp: whitespace-separated "name x y rotation value package" in
+// millimetres. Eagle writes one file per side, so the side is given.
+func ReadEagle(r io.Reader, layer Layer) ([]Placement, error) {
+	var placements []Placement
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 4 {
+			return nil, fmt.Errorf("failed to parse mount file line %d: expected at least 4 columns, got %d", line, len(fields))
+		}
+
+		var value, pkg string
+		if len(fields) > 4 {
+			value = fields[4]
+		}
+		if len(fields) > 5 {
+			pkg = strings.Join(fields[5:], " ")
+		}
+		p, err := newPlacement(fields[0], value, pkg, fields[1], fields[2], fields[3], string(layer), "mm")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse mount file line %d: %w", line, err)
+		}
+		placements = append(placements, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read mount file: %w", err)
+	}
+	return placements, nil
+}
+
+// csvColumns lists the accepted header names of each CSV column, compared
+// case-insensitively with spaces and punctuation removed, in order of
+// preference. Altium offers both centre and reference coordinates; the
+// centre wins.
+var csvColumns = map[string][]string{
+	"designator": {"designator", "ref", "reference", "refdes", "part", "name"},
+	"value":      {"comment", "value", "val"},
+	"footprint":  {"footprint", "package"},
+	"x":          {"midx", "centerxmm", "centerxmil", "centerx", "posx", "x", "refx"},
+	"y":          {"midy", "centerymm", "centerymil", "centery", "posy", "y", "refy"},
+	"rotation":   {"rotation", "rot", "angle"},
+	"layer":      {"layer", "side", "tb"},
+}
+
+// ReadCSV reads a pick-and-place CSV file with a header row, such as
+// Altium's "Pick Place" export, an Eagle CPL ULP export or an existing
+// JLCPCB CPL. Lines before the header row, like Altium's preamble, are
+// skipped. Coordinates are in millimetres unless the header says "(mil)"
+// or a value carries a "mm", "mil" or "in" suffix. Rows without a layer
+// column are placed on the top side.
+func ReadCSV(r io.Reader) ([]Placement, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	var cols map[string]int
+	unit := "mm"
+	var placements []Placement
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse placement CSV: %w", err)
+		}
+
+		if cols == nil {
+			cols, unit = csvHeader(record)
+			continue
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		get := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		layer := get("layer")
+		if layer == "" {
+			layer = string(Top)
+		}
+		p, err := newPlacement(get("designator"), get("value"), get("footprint"), get("x"), get("y"), get("rotation"), layer, unit)
+		if err != nil {
+			line, _ := cr.FieldPos(0)
+			return nil, fmt.Errorf("failed to parse placement CSV line %d: %w", line, err)
+		}
+		placements = append(placements, p)
+	}
+
+	if cols == nil {
+		return nil, fmt.Errorf("failed to parse placement CSV: no header row with designator and coordinate columns")
+	}
+	return placements, nil
+}
+
+// csvHeader maps a header row to column indexes, returning nil if the row
+// is not a header. The second result is the coordinate unit.
+func csvHeader(record []string) (map[string]int, string) {
+	index := make(map[string]int)
+	for i, name := range record {
+		index[normalizeHeader(name)] = i
+	}
+
+	cols := make(map[string]int)
+	unit := "mm"
+	for col, aliases := range csvColumns {
+		for _, alias := range aliases {
+			if i, ok := index[alias]; ok {
+				cols[col] = i
+				if col == "x" && strings.Contains(strings.ToLower(record[i]), "mil") {
+					unit = "mil"
+				}
+				break
+			}
+		}
+	}
+
+	for _, required := range []string{"designator", "x", "y"} {
+		if _, ok := cols[required]; !ok {
+			return nil, ""
+		}
+	}
+	return cols, unit
+}
+
+// normalizeHeader lower-cases a header and removes everything but letters
+// and digits.
+func normalizeHeader(name string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// newPlacement builds a placement from text fields.
+func newPlacement(designator, value, footprint, x, y, rotation, layer, unit string) (Placement, error) {
+	p := Placement{
+		Designator: strings.TrimSpace(designator),
+		Value:      strings.TrimSpace(value),
+		Footprint:  strings.TrimSpace(footprint),
+	}
+	if p.Designator == "" {
+		return Placement{}, fmt.Errorf("missing designator")
+	}
+
+	var err error
+	if p.X, err = parseLength(x, unit); err != nil {
+		return Placement{}, err
+	}
+	if p.Y, err = parseLength(y, unit); err != nil {
+		return Placement{}, err
+	}
+	rot := 0.0
+	if strings.TrimSpace(rotation) != "" {
+		rot, err = strconv.ParseFloat(strings.TrimSpace(rotation), 64)
+		if err != nil {
+			return Placement{}, fmt.Errorf("invalid rotation %q", rotation)
+		}
+	}
+	p.Rotation = normalizeRotation(rot)
+	if p.Layer, err = ParseLayer(layer); err != nil {
+		return Placement{}, err
+	}
+	return p, nil
+}
+
+// ParseLayer parses a board side as written by common EDA tools, such as
+// "top", "T", "TopLayer", "bottom", "B" or "BottomLayer".
+func ParseLayer(s string) (Layer, error) {
+	switch normalizeHeader(s) {
+	case "top", "t", "toplayer", "front", "f":
+		return Top, nil
+	case "bottom", "b", "bot", "bottomlayer", "back":
+		return Bottom, nil
+	}
+	return "", fmt.Errorf("invalid layer %q", s)
+}
+
+// parseLength parses a coordinate with an optional unit suffix into mm.
+func parseLength(s, unit string) (float64, error) {
+	text := strings.ToLower(strings.TrimSpace(s))
+	for _, suffix := range []string{"mm", "mil", "in"} {
+		if strings.HasSuffix(text, suffix) {
+			text, unit = strings.TrimSpace(strings.TrimSuffix(text, suffix)), suffix
+			break
+		}
+	}
+
+	v, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid coordinate %q", s)
+	}
+	switch unit {
+	case "mil":
+		v *= 0.0254
+	case "in":
+		v *= 25.4
+	}
+	return v, nil
+}
+
+// normalizeRotation maps a rotation into [0, 360).
+func normalizeRotation(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	if deg == 360 || deg == 0 {
+		return 0 // Also turns -0 into 0
+	}
+	return deg
+}
+
+// WriteCPL writes placements as a JLCPCB CPL in CSV format with the columns
+// Designator, Mid X, Mid Y, Layer and Rotation.
+func WriteCPL(w io.Writer, placements []Placement) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Designator", "Mid X", "Mid Y", "Layer", "Rotation"}); err != nil {
+		return fmt.Errorf("failed to write CPL: %w", err)
+	}
+	for _, p := range placements {
+		layer := p.Layer
+		if layer == "" {
+			layer = Top
+		}
+		record := []string{
+			p.Designator,
+			formatMM(p.X),
+			formatMM(p.Y),
+			string(layer),
+			strconv.FormatFloat(p.Rotation, 'f', -1, 64),
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write CPL: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write CPL: %w", err)
+	}
+	return nil
+}
+
+// formatMM formats a coordinate in millimetres.
+func formatMM(v float64) string {
+	return strconv.FormatFloat(v, 'f', 4, 64) + "mm"
+}
@@ -0,0 +1,147 @@
+package cpl
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+// TestReadKiCad tests parsing of KiCad ASCII and CSV position files.
+func TestReadKiCad(t *testing.T) {
+	ascii := `### Footprint positions - created on 2024-01-01
+## Unit = mm, Angle = deg.
+## Side : All
+# Ref     Val       Package                PosX       PosY       Rot  Side
+R1        10k       R_0402_1005Metric    120.5000   -80.2500   90.0000  top
+U1        RP2040    QFN-56-1EP_7x7mm     100.0000   -75.0000  -90.0000  bottom
+## End
+`
+	csv := `Ref,Val,Package,PosX,PosY,Rot,Side
+"R1","10k","R_0402_1005Metric",120.5,-80.25,90,top
+"U1","RP2040","QFN-56-1EP_7x7mm",100,-75,270,bottom
+`
+
+	for name, input := range map[string]string{"ascii": ascii, "csv": csv} {
+		placements, err := ReadKiCad(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("%s: ReadKiCad failed: %v", name, err)
+		}
+		if len(placements) != 2 {
+			t.Fatalf("%s: expected 2 placements, got %d", name, len(placements))
+		}
+		want := Placement{Designator: "R1", Value: "10k", Footprint: "R_0402_1005Metric", X: 120.5, Y: -80.25, Rotation: 90, Layer: Top}
+		if placements[0] != want {
+			t.Errorf("%s: unexpected placement %+v", name, placements[0])
+		}
+		if placements[1].Layer != Bottom || placements[1].Rotation != 270 {
+			t.Errorf("%s: unexpected placement %+v", name, placements[1])
+		}
+	}
+
+	if _, err := ReadKiCad(strings.NewReader("R1 10k R_0402 x 1 0 top\n")); err == nil {
+		t.Error("expected error for invalid coordinate")
+	}
+}
+
+// TestReadCSVAltium tests an Altium export with a preamble and mil units.
+func TestReadCSVAltium(t *testing.T) {
+	input := `Altium Designer Pick and Place Locations
+C:\Projects\board.PcbDoc
+
+========================================================================================================================
+File Design Information:
+
+Date:       01/01/24
+Units used: mil
+
+"Designator","Comment","Layer","Footprint","Center-X(mil)","Center-Y(mil)","Rotation","Description"
+"C1","100nF","TopLayer","C0402","1000","2000","90","Capacitor"
+"U1","STM32F103C8T6","BottomLayer","LQFP48","500.5mm","250mm","0","MCU"
+`
+	placements, err := ReadCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+	if len(placements) != 2 {
+		t.Fatalf("expected 2 placements, got %d", len(placements))
+	}
+
+	c1 := placements[0]
+	if c1.Designator != "C1" || c1.Value != "100nF" || c1.Footprint != "C0402" || c1.Layer != Top || c1.Rotation != 90 {
+		t.Errorf("unexpected placement %+v", c1)
+	}
+	if math.Abs(c1.X-25.4) > 1e-9 || math.Abs(c1.Y-50.8) > 1e-9 {
+		t.Errorf("expected mil coordinates converted to mm, got %v, %v", c1.X, c1.Y)
+	}
+	if u1 := placements[1]; u1.Layer != Bottom || u1.X != 500.5 || u1.Y != 250 {
+		t.Errorf("expected explicit mm suffix to win, got %+v", u1)
+	}
+}
+
+// TestReadCSVNoLayer tests that rows without a layer column go on top.
+func TestReadCSVNoLayer(t *testing.T) {
+	placements, err := ReadCSV(strings.NewReader("Designator,Mid X,Mid Y,Rotation\nR1,1.5,2.5,45\n"))
+	if err != nil {
+		t.Fatalf("ReadCSV failed: %v", err)
+	}
+	want := Placement{Designator: "R1", X: 1.5, Y: 2.5, Rotation: 45, Layer: Top}
+	if len(placements) != 1 || placements[0] != want {
+		t.Errorf("unexpected placements %+v", placements)
+	}
+
+	if _, err := ReadCSV(strings.NewReader("Part,Value\nR1,10k\n")); err == nil {
+		t.Error("expected error for CSV without coordinate columns")
+	}
+}
+
+// TestReadEagle tests parsing of an Eagle mount file.
+func TestReadEagle(t *testing.T) {
+	input := "R1 10.16 20.32 90 10k R0402\nIC1 5 5 0 ATMEGA328P TQFP32-08\n"
+
+	placements, err := ReadEagle(strings.NewReader(input), Bottom)
+	if err != nil {
+		t.Fatalf("ReadEagle failed: %v", err)
+	}
+	want := Placement{Designator: "R1", Value: "10k", Footprint: "R0402", X: 10.16, Y: 20.32, Rotation: 90, Layer: Bottom}
+	if len(placements) != 2 || placements[0] != want {
+		t.Errorf("unexpected placements %+v", placements)
+	}
+
+	if _, err := ReadEagle(strings.NewReader("R1 10\n"), Top); err == nil {
+		t.Error("expected error for short line")
+	}
+}
+
+// TestParseLayer tests the accepted layer spellings.
+func TestParseLayer(t *testing.T) {
+	for input, want := range map[string]Layer{"top": Top, "T": Top, "TopLayer": Top, "bottom": Bottom, "B": Bottom, "Bottom Layer": Bottom} {
+		got, err := ParseLayer(input)
+		if err != nil || got != want {
+			t.Errorf("ParseLayer(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseLayer("inner1"); err == nil {
+		t.Error("expected error for unknown layer")
+	}
+}
+
+// TestWriteCPL tests CPL output.
+func TestWriteCPL(t *testing.T) {
+	placements := []Placement{
+		{Designator: "R1", X: 120.5, Y: -80.25, Rotation: 90, Layer: Top},
+		{Designator: "U1", X: 100, Y: -75, Rotation: 180, Layer: Bottom},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCPL(&buf, placements); err != nil {
+		t.Fatalf("WriteCPL failed: %v", err)
+	}
+
+	want := "Designator,Mid X,Mid Y,Layer,Rotation\n" +
+		"R1,120.5000mm,-80.2500mm,Top,90\n" +
+		"U1,100.0000mm,-75.0000mm,Bottom,180\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CPL:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,156 @@
+package jlcpcb
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// currencyCookie is the cookie jlcpcb.com's currency selector sets. The
+// client sends it with every request so that prices come back in the
+// configured currency.
+const currencyCookie = "currency"
+
+// supportedCurrencies lists the currency codes the client accepts.
+var supportedCurrencies = map[string]bool{
+	"USD": true, "EUR": true, "GBP": true, "CAD": true, "AUD": true,
+	"JPY": true, "HKD": true, "SGD": true, "KRW": true, "CHF": true,
+	"SEK": true, "DKK": true, "NOK": true, "PLN": true, "NZD": true,
+	"INR": true, "MXN": true, "BRL": true,
+}
+
+// SupportedCurrencies returns the currency codes accepted by WithCurrency,
+// sorted alphabetically.
+func SupportedCurrencies() []string {
+	codes := make([]string, 0, len(supportedCurrencies))
+	for code := range supportedCurrencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// ValidateCurrency returns ErrInvalidInput if code is not a supported
+// currency code.
+func ValidateCurrency(code string) error {
+	if !supportedCurrencies[normalizeCurrency(code)] {
+		return ErrInvalidInput{Message: fmt.Sprintf("unsupported currency %q", code)}
+	}
+	return nil
+}
+
+// ExchangeRates is a user-supplied table for converting prices locally.
+type ExchangeRates struct {
+	Base  string             // Currency the rates are relative to, e.g. "USD"
+	Rates map[string]float64 // Units of each currency per one unit of Base
+}
+
+// Rate returns the factor that converts an amount in from into to.
+func (er ExchangeRates) Rate(from, to string) (float64, error) {
+	from, to = normalizeCurrency(from), normalizeCurrency(to)
+	if from == to {
+		return 1, nil
+	}
+
+	fromRate, err := er.unitsPerBase(from)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := er.unitsPerBase(to)
+	if err != nil {
+		return 0, err
+	}
+	return toRate / fromRate, nil
+}
+
+// ConvertProduct converts a product's prices into the to currency and
+// updates its Currency. Products without a currency are assumed to be
+// priced in Base.
+func (er ExchangeRates) ConvertProduct(p *Product, to string) error {
+	from := p.Currency
+	if from == "" {
+		from = er.Base
+	}
+
+	rate, err := er.Rate(from, to)
+	if err != nil {
+		return err
+	}
+
+	p.ComponentPrices = convertPriceBreaks(p.ComponentPrices, rate)
+	p.BuyComponentPrices = convertPriceBreaks(p.BuyComponentPrices, rate)
+	p.setCurrency(normalizeCurrency(to))
+	return nil
+}
+
+// unitsPerBase returns how many units of code equal one unit of Base.
+func (er ExchangeRates) unitsPerBase(code string) (float64, error) {
+	if code == normalizeCurrency(er.Base) {
+		return 1, nil
+	}
+	for c, rate := range er.Rates {
+		if normalizeCurrency(c) == code && rate > 0 {
+			return rate, nil
+		}
+	}
+	return 0, ErrInvalidInput{Message: fmt.Sprintf("no exchange rate for %s", code)}
+}
+
+// convertPriceBreaks returns a copy of breaks with prices multiplied by rate.
+func convertPriceBreaks(breaks []PriceBreak, rate float64) []PriceBreak {
+	if breaks == nil {
+		return nil
+	}
+	converted := make([]PriceBreak, len(breaks))
+	for i, pb := range breaks {
+		pb.ProductPrice = pb.ProductPrice.MulRate(rate)
+		converted[i] = pb
+	}
+	return converted
+}
+
+// normalizeCurrency canonicalizes a currency code.
+func normalizeCurrency(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// requestCurrency returns the currency to ask the API for. With exchange
+// rates configured the API is asked for their base currency and prices are
+// converted locally.
+func (c *Client) requestCurrency() string {
+	if c.exchangeRates != nil {
+		return normalizeCurrency(c.exchangeRates.Base)
+	}
+	return c.currency
+}
+
+// checkCurrency validates the client's currency configuration.
+func (c *Client) checkCurrency() error {
+	if err := ValidateCurrency(c.currency); err != nil {
+		return err
+	}
+	if c.exchangeRates != nil {
+		if err := ValidateCurrency(c.exchangeRates.Base); err != nil {
+			return err
+		}
+		if _, err := c.exchangeRates.Rate(c.exchangeRates.Base, c.currency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// localizeProducts tags products with the currency their prices are in,
+// converting them to the client's currency if exchange rates are configured.
+func (c *Client) localizeProducts(products []Product) error {
+	for i := range products {
+		products[i].setCurrency(c.requestCurrency())
+		if c.exchangeRates == nil {
+			continue
+		}
+		if err := c.exchangeRates.ConvertProduct(&products[i], c.currency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package jlcpcb
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// newCurrencyServer creates a search server that records the currency cookie
+// of each request.
+func newCurrencyServer(t *testing.T, products ...Product) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var currencies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(currencyCookie)
+		mu.Lock()
+		if err == nil {
+			currencies = append(currencies, cookie.Value)
+		} else {
+			currencies = append(currencies, "")
+		}
+		mu.Unlock()
+
+		var wrapper productSearchWrapper
+		wrapper.Code = 200
+		wrapper.Data.ComponentPageInfo = SearchResponse{Products: products, TotalCount: len(products)}
+		_ = json.NewEncoder(w).Encode(wrapper)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), currencies...)
+	}
+}
+
+// TestValidateCurrency tests currency code validation.
+func TestValidateCurrency(t *testing.T) {
+	for _, code := range []string{"USD", "eur", " GBP "} {
+		if err := ValidateCurrency(code); err != nil {
+			t.Errorf("expected %q to be valid, got %v", code, err)
+		}
+	}
+	for _, code := range []string{"", "XYZ", "US"} {
+		if _, ok := ValidateCurrency(code).(ErrInvalidInput); !ok {
+			t.Errorf("expected ErrInvalidInput for %q", code)
+		}
+	}
+
+	codes := SupportedCurrencies()
+	if len(codes) == 0 || codes[0] > codes[len(codes)-1] {
+		t.Errorf("expected sorted currency list, got %v", codes)
+	}
+}
+
+// TestClientSendsCurrency tests that the configured currency is requested and tagged.
+func TestClientSendsCurrency(t *testing.T) {
+	server, currencies := newCurrencyServer(t, Product{ComponentCode: "C1"})
+	client := NewClient(WithBaseURL(server.URL), WithCurrency("eur"))
+
+	resp, err := client.KeywordSearch(context.Background(), SearchRequest{Keyword: "C1"})
+	if err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+
+	if got := currencies(); len(got) != 1 || got[0] != "EUR" {
+		t.Errorf("expected currency cookie EUR, got %v", got)
+	}
+	if resp.Products[0].Currency != "EUR" {
+		t.Errorf("expected product currency EUR, got %q", resp.Products[0].Currency)
+	}
+}
+
+// TestClientInvalidCurrency tests that unsupported currencies fail before any request.
+func TestClientInvalidCurrency(t *testing.T) {
+	server, currencies := newCurrencyServer(t)
+	client := NewClient(WithBaseURL(server.URL), WithCurrency("XYZ"))
+
+	_, err := client.KeywordSearch(context.Background(), SearchRequest{Keyword: "C1"})
+	if _, ok := err.(ErrInvalidInput); !ok {
+		t.Errorf("expected ErrInvalidInput, got %v", err)
+	}
+	_, err = client.GetProductDetails(context.Background(), "C1")
+	if _, ok := err.(ErrInvalidInput); !ok {
+		t.Errorf("expected ErrInvalidInput, got %v", err)
+	}
+	if got := currencies(); len(got) != 0 {
+		t.Errorf("expected no requests, got %d", len(got))
+	}
+}
+
+// TestClientExchangeRates tests local price conversion.
+func TestClientExchangeRates(t *testing.T) {
+	server, currencies := newCurrencyServer(t, Product{
+		ComponentCode:   "C1",
+		ComponentPrices: []PriceBreak{{StartNumber: 1, ProductPrice: MoneyFromFloat(2, "")}},
+	})
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithCurrency("EUR"),
+		WithExchangeRates(ExchangeRates{Base: "USD", Rates: map[string]float64{"EUR": 0.9}}),
+	)
+
+	product, err := client.GetProductDetails(context.Background(), "C1")
+	if err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+
+	if got := currencies(); len(got) != 1 || got[0] != "USD" {
+		t.Errorf("expected prices requested in USD, got %v", got)
+	}
+	if product.Currency != "EUR" {
+		t.Errorf("expected currency EUR, got %q", product.Currency)
+	}
+	if price := product.ComponentPrices[0].ProductPrice; price != MoneyFromFloat(1.8, "EUR") {
+		t.Errorf("expected converted price 1.8 EUR, got %v", price)
+	}
+}
+
+// TestClientMissingExchangeRate tests that a missing rate is reported as invalid input.
+func TestClientMissingExchangeRate(t *testing.T) {
+	client := NewClient(
+		WithCurrency("GBP"),
+		WithExchangeRates(ExchangeRates{Base: "USD", Rates: map[string]float64{"EUR": 0.9}}),
+	)
+
+	_, err := client.KeywordSearch(context.Background(), SearchRequest{Keyword: "C1"})
+	if _, ok := err.(ErrInvalidInput); !ok {
+		t.Errorf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+// TestExchangeRatesRate tests conversion between two non-base currencies.
+func TestExchangeRatesRate(t *testing.T) {
+	rates := ExchangeRates{Base: "USD", Rates: map[string]float64{"EUR": 0.8, "GBP": 0.6}}
+
+	rate, err := rates.Rate("eur", "GBP")
+	if err != nil {
+		t.Fatalf("Rate failed: %v", err)
+	}
+	if math.Abs(rate-0.75) > 1e-9 {
+		t.Errorf("expected rate 0.75, got %f", rate)
+	}
+
+	if rate, _ := rates.Rate("EUR", "EUR"); rate != 1 {
+		t.Errorf("expected identity rate 1, got %f", rate)
+	}
+}
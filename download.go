@@ -0,0 +1,306 @@
+package jlcpcb
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	siteURL         = "https://jlcpcb.com/"
+	maxDownloadSize = 64 << 20 // largest datasheet or image accepted, in bytes
+)
+
+// assetKind describes a downloadable product asset.
+type assetKind struct {
+	name   string                                     // used in errors
+	accept string                                     // Accept header
+	ext    string                                     // default extension in an AssetStore
+	check  func(contentType string, body []byte) bool // verifies the content
+}
+
+var (
+	datasheetAsset = assetKind{
+		name:   "datasheet",
+		accept: "application/pdf,*/*;q=0.8",
+		ext:    ".pdf",
+		check: func(contentType string, body []byte) bool {
+			// Datasheets are often served as application/octet-stream, so
+			// the PDF signature decides.
+			return bytes.HasPrefix(body, []byte("%PDF-"))
+		},
+	}
+	imageAsset = assetKind{
+		name:   "image",
+		accept: "image/*,*/*;q=0.8",
+		check:  isImage,
+	}
+)
+
+// isImage reports whether a response is an image. Binary formats are
+// recognized by their signature. Text-based SVG has none, so it is accepted
+// by its markup or by an image/* Content-Type, as long as sniffing does not
+// identify the body as something else, such as an HTML error page.
+func isImage(contentType string, body []byte) bool {
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(body))
+	if strings.HasPrefix(sniffed, "image/") {
+		return true
+	}
+	switch sniffed {
+	case "text/plain", "text/xml", "application/octet-stream":
+	default:
+		return false
+	}
+
+	declared, _, err := mime.ParseMediaType(contentType)
+	return (err == nil && strings.HasPrefix(declared, "image/")) || isSVG(body)
+}
+
+// isSVG reports whether body starts with SVG markup, after any XML prolog,
+// comments or doctype.
+func isSVG(body []byte) bool {
+	head := bytes.ToLower(body[:min(len(body), 1024)])
+	i := bytes.Index(head, []byte("<svg"))
+	if i < 0 {
+		return false
+	}
+	// Only declarations and comments may come before the root element.
+	for _, tag := range bytes.Split(head[:i], []byte("<"))[1:] {
+		if len(tag) == 0 || (tag[0] != '?' && tag[0] != '!') {
+			return false
+		}
+	}
+	return true
+}
+
+// DownloadDatasheet downloads the product's datasheet PDF to w. The request
+// goes through the client's rate limiter and retry policy and follows
+// redirects. It fails with ErrUnexpectedContent if the response is not a PDF,
+// in which case nothing is written to w.
+func (c *Client) DownloadDatasheet(ctx context.Context, product *Product, w io.Writer) error {
+	_, err := c.downloadAsset(ctx, product.DataManualUrl, product.ComponentCode, datasheetAsset, w)
+	return err
+}
+
+// DownloadImage downloads the product's main image to w. It uses
+// ComponentImageUrl, falling back to the first entry of ImageList and then
+// MinImage. It fails with ErrUnexpectedContent if the response is not an
+// image.
+func (c *Client) DownloadImage(ctx context.Context, product *Product, w io.Writer) error {
+	_, err := c.downloadAsset(ctx, product.ImageURL(), product.ComponentCode, imageAsset, w)
+	return err
+}
+
+// SaveDatasheet downloads the product's datasheet into store and returns
+// where it was stored.
+func (c *Client) SaveDatasheet(ctx context.Context, product *Product, store *AssetStore) (Asset, error) {
+	var buf bytes.Buffer
+	if _, err := c.downloadAsset(ctx, product.DataManualUrl, product.ComponentCode, datasheetAsset, &buf); err != nil {
+		return Asset{}, err
+	}
+	return store.Put(&buf, datasheetAsset.ext)
+}
+
+// SaveImage downloads the product's main image into store and returns where
+// it was stored.
+func (c *Client) SaveImage(ctx context.Context, product *Product, store *AssetStore) (Asset, error) {
+	var buf bytes.Buffer
+	contentType, err := c.downloadAsset(ctx, product.ImageURL(), product.ComponentCode, imageAsset, &buf)
+	if err != nil {
+		return Asset{}, err
+	}
+	return store.Put(&buf, imageExtension(contentType, buf.Bytes()))
+}
+
+// ImageURL returns the URL of the product's main image, or "" if it has none.
+func (p *Product) ImageURL() string {
+	if p.ComponentImageUrl != "" {
+		return p.ComponentImageUrl
+	}
+	for _, img := range p.ImageList {
+		if img.ProductBigImage != "" {
+			return img.ProductBigImage
+		}
+	}
+	return p.MinImage
+}
+
+// downloadAsset fetches rawURL, verifies it, writes it to w and returns the
+// response content type.
+func (c *Client) downloadAsset(ctx context.Context, rawURL, partCode string, kind assetKind, w io.Writer) (string, error) {
+	if strings.TrimSpace(rawURL) == "" {
+		return "", ErrInvalidInput{Message: fmt.Sprintf("product %s has no %s URL", partCode, kind.name)}
+	}
+	u, err := resolveAssetURL(rawURL)
+	if err != nil {
+		return "", ErrInvalidInput{Message: fmt.Sprintf("invalid %s URL %q: %v", kind.name, rawURL, err)}
+	}
+
+	var contentType string
+	body, err := c.withRetries(ctx, http.MethodGet, u.Path, func(ctx context.Context) ([]byte, int, error) {
+		body, status, ct, err := c.executeDownload(ctx, u.String(), kind.accept)
+		contentType = ct
+		return body, status, err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if !kind.check(contentType, body) {
+		return "", ErrUnexpectedContent{URL: u.String(), ContentType: contentType, Want: kind.name}
+	}
+
+	if _, err := w.Write(body); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", kind.name, err)
+	}
+	return contentType, nil
+}
+
+// executeDownload performs a single GET of an asset URL.
+func (c *Client) executeDownload(ctx context.Context, rawURL, accept string) ([]byte, int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, 0, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", accept)
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	req.Header.Set("Referer", "https://jlcpcb.com/parts")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, "", fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	contentType := resp.Header.Get("Content-Type")
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp.StatusCode, contentType, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := readBody(resp, maxDownloadSize)
+	if err != nil {
+		return nil, resp.StatusCode, contentType, err
+	}
+	return body, resp.StatusCode, contentType, nil
+}
+
+// resolveAssetURL resolves relative and protocol-relative asset URLs
+// against the JLCPCB site.
+func resolveAssetURL(rawURL string) (*url.URL, error) {
+	base, _ := url.Parse(siteURL)
+	u, err := base.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	return u, nil
+}
+
+// imageExtension picks a file extension for image data.
+func imageExtension(contentType string, body []byte) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "image/") {
+		mediaType = http.DetectContentType(body)
+		if isSVG(body) {
+			mediaType = "image/svg+xml"
+		}
+	}
+	switch mediaType {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "image/svg+xml":
+		return ".svg"
+	default:
+		return ".img"
+	}
+}
+
+// Asset describes a file stored in an AssetStore.
+type Asset struct {
+	Path   string // Location of the file
+	SHA256 string // Hex SHA-256 of the contents
+	Size   int64  // Size in bytes
+}
+
+// AssetStore is a content-addressed directory of downloaded files. Files are
+// named by the SHA-256 of their contents, so identical datasheets shared by
+// several parts are stored once.
+type AssetStore struct {
+	dir string
+}
+
+// NewAssetStore creates an asset store rooted at dir. The directory is
+// created on first use.
+func NewAssetStore(dir string) *AssetStore {
+	return &AssetStore{dir: dir}
+}
+
+// Put stores the contents of r with the given extension and returns its
+// location. Storing the same contents again returns the existing file.
+func (as *AssetStore) Put(r io.Reader, ext string) (Asset, error) {
+	if err := os.MkdirAll(as.dir, 0o755); err != nil {
+		return Asset{}, fmt.Errorf("failed to create asset directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(as.dir, ".asset-*")
+	if err != nil {
+		return Asset{}, fmt.Errorf("failed to create asset file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), r)
+	if err != nil {
+		_ = tmp.Close()
+		return Asset{}, fmt.Errorf("failed to write asset: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return Asset{}, fmt.Errorf("failed to write asset: %w", err)
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	asset := Asset{Path: as.Path(sum, ext), SHA256: sum, Size: size}
+	if _, err := os.Stat(asset.Path); err == nil {
+		return asset, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(asset.Path), 0o755); err != nil {
+		return Asset{}, fmt.Errorf("failed to create asset directory: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), asset.Path); err != nil {
+		return Asset{}, fmt.Errorf("failed to store asset: %w", err)
+	}
+	return asset, nil
+}
+
+// Path returns where contents with the given SHA-256 and extension are
+// stored. Files are spread over subdirectories named by the first two hex
+// digits of the hash.
+func (as *AssetStore) Path(sum, ext string) string {
+	if len(sum) < 2 {
+		return filepath.Join(as.dir, sum+ext)
+	}
+	return filepath.Join(as.dir, sum[:2], sum+ext)
+}
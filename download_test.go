@@ -0,0 +1,222 @@
+package jlcpcb
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+var (
+	testPDF = []byte("%PDF-1.4\n% test datasheet\n%%EOF\n")
+	testPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	testSVG = []byte(`<?xml version="1.0"?>\n<!-- logo --><svg xmlns="http://www.w3.org/2000/svg"/>`)
+)
+
+// countingLimiter counts Wait calls.
+type countingLimiter struct{ waits int32 }
+
+func (cl *countingLimiter) Wait(ctx context.Context) error {
+	atomic.AddInt32(&cl.waits, 1)
+	return nil
+}
+
+// newAssetServer serves a PDF, a PNG, an HTML error page and a redirect.
+// The first failPDF requests for the PDF fail with 503.
+func newAssetServer(t *testing.T, failPDF int32) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var pdfRequests int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/files/datasheet.pdf", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != userAgent {
+			t.Errorf("expected client User-Agent, got %q", r.Header.Get("User-Agent"))
+		}
+		if atomic.AddInt32(&pdfRequests, 1) <= failPDF {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(testPDF)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/files/datasheet.pdf", http.StatusFound)
+	})
+	mux.HandleFunc("/error.html", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html>Access denied</html>"))
+	})
+	mux.HandleFunc("/images/c1.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(testPNG)
+	})
+	mux.HandleFunc("/images/c1.svg", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/svg+xml")
+		_, _ = w.Write([]byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`))
+	})
+	mux.HandleFunc("/images/logo", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(testSVG)
+	})
+	mux.HandleFunc("/images/denied.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("<html>Access denied</html>"))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server, &pdfRequests
+}
+
+// TestDownloadDatasheet tests a datasheet download with a retry and a redirect.
+func TestDownloadDatasheet(t *testing.T) {
+	server, pdfRequests := newAssetServer(t, 1)
+	limiter := &countingLimiter{}
+	client := NewClient(WithRetryConfig(fastRetryConfig()), WithLimiter(limiter))
+
+	var buf bytes.Buffer
+	product := &Product{ComponentCode: "C1", DataManualUrl: server.URL + "/redirect"}
+	if err := client.DownloadDatasheet(context.Background(), product, &buf); err != nil {
+		t.Fatalf("DownloadDatasheet failed: %v", err)
+	}
+
+	if !bytes.Equal(buf.Bytes(), testPDF) {
+		t.Errorf("unexpected datasheet contents %q", buf.Bytes())
+	}
+	if *pdfRequests != 2 {
+		t.Errorf("expected 2 requests after one retry, got %d", *pdfRequests)
+	}
+	if atomic.LoadInt32(&limiter.waits) != 2 {
+		t.Errorf("expected each attempt to wait on the limiter, got %d waits", limiter.waits)
+	}
+}
+
+// TestDownloadDatasheetNotPDF tests that non-PDF responses are rejected.
+func TestDownloadDatasheetNotPDF(t *testing.T) {
+	server, _ := newAssetServer(t, 0)
+	client := NewClient()
+
+	var buf bytes.Buffer
+	product := &Product{ComponentCode: "C1", DataManualUrl: server.URL + "/error.html"}
+	err := client.DownloadDatasheet(context.Background(), product, &buf)
+
+	contentErr, ok := err.(ErrUnexpectedContent)
+	if !ok {
+		t.Fatalf("expected ErrUnexpectedContent, got %v", err)
+	}
+	if contentErr.ContentType != "text/html" {
+		t.Errorf("expected text/html, got %q", contentErr.ContentType)
+	}
+	if buf.Len() != 0 {
+		t.Error("expected nothing to be written")
+	}
+}
+
+// TestDownloadMissingURL tests products without asset URLs.
+func TestDownloadMissingURL(t *testing.T) {
+	client := NewClient()
+	product := &Product{ComponentCode: "C1"}
+
+	if _, ok := client.DownloadDatasheet(context.Background(), product, &bytes.Buffer{}).(ErrInvalidInput); !ok {
+		t.Error("expected ErrInvalidInput for missing datasheet URL")
+	}
+	if _, ok := client.DownloadImage(context.Background(), product, &bytes.Buffer{}).(ErrInvalidInput); !ok {
+		t.Error("expected ErrInvalidInput for missing image URL")
+	}
+}
+
+// TestDownloadImage tests image downloads and the image URL fallbacks.
+func TestDownloadImage(t *testing.T) {
+	server, _ := newAssetServer(t, 0)
+	client := NewClient()
+
+	product := &Product{
+		ComponentCode: "C1",
+		ImageList:     []ProductImage{{ProductBigImage: server.URL + "/images/c1.png"}},
+		MinImage:      server.URL + "/images/missing.png",
+	}
+	if product.ImageURL() != server.URL+"/images/c1.png" {
+		t.Errorf("expected image list fallback, got %s", product.ImageURL())
+	}
+
+	var buf bytes.Buffer
+	if err := client.DownloadImage(context.Background(), product, &buf); err != nil {
+		t.Fatalf("DownloadImage failed: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), testPNG) {
+		t.Errorf("unexpected image contents %q", buf.Bytes())
+	}
+
+	for _, path := range []string{"/images/c1.svg", "/images/logo"} {
+		product.ComponentImageUrl = server.URL + path
+		if err := client.DownloadImage(context.Background(), product, &buf); err != nil {
+			t.Errorf("%s: expected an SVG image, got %v", path, err)
+		}
+	}
+	if ext := imageExtension("application/octet-stream", testSVG); ext != ".svg" {
+		t.Errorf("expected .svg for sniffed SVG, got %s", ext)
+	}
+
+	for _, path := range []string{"/files/datasheet.pdf", "/images/denied.png"} {
+		product.ComponentImageUrl = server.URL + path
+		if _, ok := client.DownloadImage(context.Background(), product, &buf).(ErrUnexpectedContent); !ok {
+			t.Errorf("%s: expected ErrUnexpectedContent", path)
+		}
+	}
+}
+
+// TestSaveToAssetStore tests content-addressed storage of downloads.
+func TestSaveToAssetStore(t *testing.T) {
+	server, _ := newAssetServer(t, 0)
+	client := NewClient()
+	store := NewAssetStore(t.TempDir())
+
+	a, err := client.SaveDatasheet(context.Background(), &Product{ComponentCode: "C1", DataManualUrl: server.URL + "/files/datasheet.pdf"}, store)
+	if err != nil {
+		t.Fatalf("SaveDatasheet failed: %v", err)
+	}
+	b, err := client.SaveDatasheet(context.Background(), &Product{ComponentCode: "C2", DataManualUrl: server.URL + "/redirect"}, store)
+	if err != nil {
+		t.Fatalf("SaveDatasheet failed: %v", err)
+	}
+
+	if a != b {
+		t.Errorf("expected identical datasheets to share a file, got %+v and %+v", a, b)
+	}
+	if a.Size != int64(len(testPDF)) || a.Path != store.Path(a.SHA256, ".pdf") {
+		t.Errorf("unexpected asset %+v", a)
+	}
+	if data, err := os.ReadFile(a.Path); err != nil || !bytes.Equal(data, testPDF) {
+		t.Errorf("unexpected stored contents: %v", err)
+	}
+
+	img, err := client.SaveImage(context.Background(), &Product{ComponentCode: "C1", ComponentImageUrl: server.URL + "/images/c1.png"}, store)
+	if err != nil {
+		t.Fatalf("SaveImage failed: %v", err)
+	}
+	if img.Path != store.Path(img.SHA256, ".png") {
+		t.Errorf("expected .png asset, got %s", img.Path)
+	}
+}
+
+// TestResolveAssetURL tests resolving relative asset URLs against the site.
+func TestResolveAssetURL(t *testing.T) {
+	tests := map[string]string{
+		"/api/file/1":                  "https://jlcpcb.com/api/file/1",
+		"//assets.example.com/img.jpg": "https://assets.example.com/img.jpg",
+		"http://example.com/a.pdf":     "http://example.com/a.pdf",
+	}
+	for input, want := range tests {
+		u, err := resolveAssetURL(input)
+		if err != nil || u.String() != want {
+			t.Errorf("resolveAssetURL(%q) = %v, %v; want %s", input, u, err, want)
+		}
+	}
+
+	if _, err := resolveAssetURL("file:///etc/passwd"); err == nil {
+		t.Error("expected error for non-HTTP scheme")
+	}
+}
@@ -27,6 +27,18 @@ func (e ErrRateLimited) Error() string {
 	return "rate limited by API"
 }
 
+// ErrUnexpectedContent indicates a download returned the wrong kind of
+// content, such as an HTML error page instead of a PDF datasheet.
+type ErrUnexpectedContent struct {
+	URL         string
+	ContentType string
+	Want        string
+}
+
+func (e ErrUnexpectedContent) Error() string {
+	return fmt.Sprintf("unexpected content from %s: got %q, want %s", e.URL, e.ContentType, e.Want)
+}
+
 // errorFromCode converts an API error code to an error.
 func errorFromCode(code int, message string) error {
 	switch code {
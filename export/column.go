@@ -0,0 +1,147 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+// Column is one exported field. Value returns a string, number, bool,
+// jlcpcb.Money or nil for a missing value.
+type Column struct {
+	Name  string // CSV header and NDJSON key
+	Value func(p *jlcpcb.Product) interface{}
+}
+
+// fieldColumns are the named product fields accepted by ParseColumns.
+var fieldColumns = map[string]func(p *jlcpcb.Product) interface{}{
+	"code":         func(p *jlcpcb.Product) interface{} { return p.ComponentCode },
+	"mpn":          func(p *jlcpcb.Product) interface{} { return p.ComponentModelEn },
+	"manufacturer": func(p *jlcpcb.Product) interface{} { return p.ComponentBrandEn },
+	"description":  func(p *jlcpcb.Product) interface{} { return p.Describe },
+	"package":      func(p *jlcpcb.Product) interface{} { return p.ComponentSpecificationEn },
+	"category":     func(p *jlcpcb.Product) interface{} { return p.FirstSortName },
+	"subcategory":  func(p *jlcpcb.Product) interface{} { return p.SecondSortName },
+	"stock":        func(p *jlcpcb.Product) interface{} { return p.StockCount },
+	"min_order":    func(p *jlcpcb.Product) interface{} { return p.MinPurchaseNum },
+	"library_type": func(p *jlcpcb.Product) interface{} { return p.ComponentLibraryType },
+	"basic":        func(p *jlcpcb.Product) interface{} { return p.IsBasic() },
+	"preferred":    func(p *jlcpcb.Product) interface{} { return p.IsPreferred() },
+	"assembly":     func(p *jlcpcb.Product) interface{} { return p.AssemblyProcess },
+	"currency":     func(p *jlcpcb.Product) interface{} { return p.Currency },
+	"datasheet":    func(p *jlcpcb.Product) interface{} { return p.DataManualUrl },
+	"image":        func(p *jlcpcb.Product) interface{} { return p.ImageURL() },
+	"url":          func(p *jlcpcb.Product) interface{} { return p.GetProductURL() },
+	"attributes":   attributesValue,
+}
+
+// DefaultColumns are used when Options.Columns is empty.
+var DefaultColumns = []string{"code", "mpn", "manufacturer", "package", "description", "stock", "min_order", "library_type", "price@1", "url"}
+
+// ParseColumns builds columns from specs. A spec is one of
+//
+//	code, mpn, manufacturer, description, package, category, subcategory,
+//	stock, min_order, library_type, basic, preferred, assembly, currency,
+//	datasheet, image, url, attributes
+//	attr:NAME    the value of attribute NAME, e.g. "attr:Resistance"
+//	price@N      the unit price when ordering N, e.g. "price@100"
+//	total@N      the extended price when ordering N
+//
+// "attributes" joins all attributes as "Name=Value; ...". Specs are
+// matched case-insensitively except for attribute names, which are matched
+// case-insensitively against the product.
+func ParseColumns(specs []string) ([]Column, error) {
+	columns := make([]Column, 0, len(specs))
+	for _, spec := range specs {
+		col, err := parseColumn(strings.TrimSpace(spec))
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+// parseColumn builds one column from its spec.
+func parseColumn(spec string) (Column, error) {
+	lower := strings.ToLower(spec)
+	switch {
+	case strings.HasPrefix(lower, "attr:"):
+		name := strings.TrimSpace(spec[len("attr:"):])
+		if name == "" {
+			return Column{}, fmt.Errorf("invalid column %q: missing attribute name", spec)
+		}
+		return Column{Name: spec, Value: attributeValue(name)}, nil
+
+	case strings.HasPrefix(lower, "price@"), strings.HasPrefix(lower, "total@"):
+		kind, n, _ := strings.Cut(lower, "@")
+		quantity, err := strconv.Atoi(n)
+		if err != nil || quantity < 1 {
+			return Column{}, fmt.Errorf("invalid column %q: quantity must be a positive integer", spec)
+		}
+		return Column{Name: lower, Value: priceValue(quantity, kind == "total")}, nil
+	}
+
+	value, ok := fieldColumns[lower]
+	if !ok {
+		return Column{}, fmt.Errorf("invalid column %q: unknown field", spec)
+	}
+	return Column{Name: lower, Value: value}, nil
+}
+
+// attributeValue returns a column value reading one attribute.
+func attributeValue(name string) func(p *jlcpcb.Product) interface{} {
+	return func(p *jlcpcb.Product) interface{} {
+		for _, attr := range p.Attributes {
+			if strings.EqualFold(attr.Name, name) {
+				return attr.Value
+			}
+		}
+		return nil
+	}
+}
+
+// attributesValue joins all attributes of a product.
+func attributesValue(p *jlcpcb.Product) interface{} {
+	parts := make([]string, len(p.Attributes))
+	for i, attr := range p.Attributes {
+		parts[i] = attr.Name + "=" + attr.Value
+	}
+	return strings.Join(parts, "; ")
+}
+
+// priceValue returns a column value reading the unit or extended price at
+// a quantity.
+func priceValue(quantity int, total bool) func(p *jlcpcb.Product) interface{} {
+	return func(p *jlcpcb.Product) interface{} {
+		price, ok := p.UnitPrice(quantity)
+		if total {
+			price, ok = p.ExtendedPrice(quantity)
+		}
+		if !ok {
+			return nil
+		}
+		return price
+	}
+}
+
+// formatCSV formats a column value for CSV. Prices are written as plain
+// decimals; their currency can be exported with the "currency" column.
+func formatCSV(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case jlcpcb.Money:
+		return v.Decimal()
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"testing"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+// testProduct returns a product with attributes and price tiers.
+func testProduct() *jlcpcb.Product {
+	return &jlcpcb.Product{
+		ComponentCode:            "C25744",
+		ComponentModelEn:         "0402WGF1002TCE",
+		ComponentSpecificationEn: "0402",
+		ComponentLibraryType:     "base",
+		StockCount:               1000,
+		Currency:                 "USD",
+		Attributes: []jlcpcb.Attribute{
+			{Name: "Resistance", Value: "10kΩ"},
+			{Name: "Tolerance", Value: "±1%"},
+		},
+		ComponentPrices: []jlcpcb.PriceBreak{
+			{StartNumber: 100, EndNumber: 9999, ProductPrice: jlcpcb.MoneyFromFloat(0.0005, "USD")},
+			{StartNumber: 10000, EndNumber: -1, ProductPrice: jlcpcb.MoneyFromFloat(0.0004, "USD")},
+		},
+	}
+}
+
+// TestParseColumns tests field, attribute and price columns.
+func TestParseColumns(t *testing.T) {
+	columns, err := ParseColumns([]string{"Code", "attr:resistance", "attr:Power", "price@10000", "total@100", "basic", "attributes"})
+	if err != nil {
+		t.Fatalf("ParseColumns failed: %v", err)
+	}
+
+	p := testProduct()
+	want := []struct {
+		name  string
+		value string
+	}{
+		{"code", "C25744"},
+		{"attr:resistance", "10kΩ"},
+		{"attr:Power", ""},
+		{"price@10000", "0.0004"},
+		{"total@100", "0.05"},
+		{"basic", "true"},
+		{"attributes", "Resistance=10kΩ; Tolerance=±1%"},
+	}
+	for i, w := range want {
+		if columns[i].Name != w.name {
+			t.Errorf("column %d: expected name %q, got %q", i, w.name, columns[i].Name)
+		}
+		if got := formatCSV(columns[i].Value(p)); got != w.value {
+			t.Errorf("column %s: expected %q, got %q", w.name, w.value, got)
+		}
+	}
+}
+
+// TestParseColumnsInvalid tests rejected specs.
+func TestParseColumnsInvalid(t *testing.T) {
+	for _, spec := range []string{"nope", "attr:", "price@0", "price@x", "total@"} {
+		if _, err := ParseColumns([]string{spec}); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+	if _, err := ParseColumns(DefaultColumns); err != nil {
+		t.Errorf("expected default columns to parse: %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+// Package export streams products to NDJSON or CSV with configurable
+// columns, including single attributes and prices at chosen quantities.
+// Rows are written as products arrive, so exporting a whole category uses
+// no more memory than one page of results.
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+// Format is an output format.
+type Format int
+
+const (
+	// NDJSON writes one JSON object per line. Without explicit columns each
+	// object is the full product, including unmapped upstream fields.
+	NDJSON Format = iota
+	// CSV writes a header row followed by one row per product.
+	CSV
+)
+
+// ParseFormat parses "ndjson", "jsonl" or "csv".
+func ParseFormat(s string) (Format, error) {
+	switch s {
+	case "ndjson", "jsonl":
+		return NDJSON, nil
+	case "csv":
+		return CSV, nil
+	}
+	return 0, fmt.Errorf("unknown export format %q", s)
+}
+
+// Options configures an export.
+type Options struct {
+	Format  Format
+	Columns []Column // Columns to write; CSV defaults to DefaultColumns
+}
+
+// Writer writes products one at a time. Call Flush when done.
+type Writer struct {
+	format  Format
+	columns []Column
+	bw      *bufio.Writer
+	csv     *csv.Writer
+	header  bool
+}
+
+// NewWriter returns a writer for opts. It fails for an unknown format or
+// an invalid DefaultColumns spec.
+func NewWriter(w io.Writer, opts Options) (*Writer, error) {
+	columns := opts.Columns
+	if len(columns) == 0 && opts.Format == CSV {
+		var err error
+		if columns, err = ParseColumns(DefaultColumns); err != nil {
+			return nil, err
+		}
+	}
+
+	ew := &Writer{format: opts.Format, columns: columns}
+	switch opts.Format {
+	case NDJSON:
+		ew.bw = bufio.NewWriter(w)
+	case CSV:
+		ew.csv = csv.NewWriter(w)
+	default:
+		return nil, fmt.Errorf("unknown export format %d", opts.Format)
+	}
+	return ew, nil
+}
+
+// Write writes one product.
+func (ew *Writer) Write(p *jlcpcb.Product) error {
+	if ew.format == CSV {
+		return ew.writeCSV(p)
+	}
+	return ew.writeNDJSON(p)
+}
+
+// writeCSV writes a CSV row, preceded by the header on the first call.
+func (ew *Writer) writeCSV(p *jlcpcb.Product) error {
+	if err := ew.writeHeader(); err != nil {
+		return err
+	}
+
+	record := make([]string, len(ew.columns))
+	for i, col := range ew.columns {
+		record[i] = formatCSV(col.Value(p))
+	}
+	if err := ew.csv.Write(record); err != nil {
+		return fmt.Errorf("failed to write CSV row for %s: %w", p.ComponentCode, err)
+	}
+	return nil
+}
+
+// writeHeader writes the CSV header unless it was already written.
+func (ew *Writer) writeHeader() error {
+	if ew.header {
+		return nil
+	}
+	names := make([]string, len(ew.columns))
+	for i, col := range ew.columns {
+		names[i] = col.Name
+	}
+	if err := ew.csv.Write(names); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	ew.header = true
+	return nil
+}
+
+// writeNDJSON writes one JSON line. With columns, keys are written in
+// column order.
+func (ew *Writer) writeNDJSON(p *jlcpcb.Product) error {
+	var data []byte
+	var err error
+	if len(ew.columns) == 0 {
+		data, err = json.Marshal(p)
+	} else {
+		data, err = ew.marshalColumns(p)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", p.ComponentCode, err)
+	}
+
+	data = append(data, '\n')
+	if _, err := ew.bw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", p.ComponentCode, err)
+	}
+	return nil
+}
+
+// marshalColumns encodes the columns of p as an ordered JSON object.
+func (ew *Writer) marshalColumns(p *jlcpcb.Product) ([]byte, error) {
+	buf := []byte{'{'}
+	for i, col := range ew.columns {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		key, err := json.Marshal(col.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(col.Value(p))
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, key...)
+		buf = append(buf, ':')
+		buf = append(buf, value...)
+	}
+	return append(buf, '}'), nil
+}
+
+// Flush writes any buffered data. For CSV with no products, it writes the
+// header alone.
+func (ew *Writer) Flush() error {
+	if ew.format == CSV {
+		if err := ew.writeHeader(); err != nil {
+			return err
+		}
+		ew.csv.Flush()
+		if err := ew.csv.Error(); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+		return nil
+	}
+	if err := ew.bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write NDJSON: %w", err)
+	}
+	return nil
+}
+
+// Search pages through every result of req and writes each product to w,
+// flushing after each page. It returns the number of products written. On
+// error, the products of earlier pages have already been written.
+func Search(ctx context.Context, api jlcpcb.PartsAPI, req jlcpcb.SearchRequest, w io.Writer, opts Options) (int, error) {
+	ew, err := NewWriter(w, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	written := 0
+	pager := jlcpcb.NewPager(api, req)
+	for pager.Next(ctx) {
+		products := pager.Page().Products
+		for i := range products {
+			if err := ew.Write(&products[i]); err != nil {
+				return written, err
+			}
+			written++
+		}
+		if err := ew.Flush(); err != nil {
+			return written, err
+		}
+	}
+	if err := pager.Err(); err != nil {
+		return written, fmt.Errorf("export %q page %d: %w", req.Keyword, pager.PageNumber(), err)
+	}
+	return written, ew.Flush()
+}
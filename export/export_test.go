@@ -0,0 +1,201 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+	"github.com/PatrickWalther/go-jlcpcb-parts/jlcpcbtest"
+)
+
+// TestWriterCSV tests CSV output with a header.
+func TestWriterCSV(t *testing.T) {
+	columns, err := ParseColumns([]string{"code", "package", "attr:Resistance", "price@100"})
+	if err != nil {
+		t.Fatalf("ParseColumns failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Options{Format: CSV, Columns: columns})
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	if err := w.Write(testProduct()); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	want := "code,package,attr:Resistance,price@100\nC25744,0402,10kΩ,0.0005\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CSV:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+// TestWriterCSVEmpty tests that an empty CSV export still has a header.
+func TestWriterCSVEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Options{Format: CSV})
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if want := strings.Join(DefaultColumns, ",") + "\n"; buf.String() != want {
+		t.Errorf("expected header only, got %q", buf.String())
+	}
+}
+
+// TestWriterNDJSON tests full and column NDJSON output.
+func TestWriterNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Options{Format: NDJSON})
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	p := testProduct()
+	p.Extras = map[string]json.RawMessage{"rohsFlag": json.RawMessage("true")}
+	if err := w.Write(p); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	var full map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &full); err != nil {
+		t.Fatalf("invalid NDJSON line: %v", err)
+	}
+	if string(full["componentCode"]) != `"C25744"` || string(full["rohsFlag"]) != "true" {
+		t.Errorf("expected full product with extras, got %s", buf.String())
+	}
+
+	columns, err := ParseColumns([]string{"code", "stock", "attr:Power", "price@10000"})
+	if err != nil {
+		t.Fatalf("ParseColumns failed: %v", err)
+	}
+	buf.Reset()
+	w, err = NewWriter(&buf, Options{Format: NDJSON, Columns: columns})
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	if err := w.Write(testProduct()); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	want := `{"code":"C25744","stock":1000,"attr:Power":null,"price@10000":0.0004}` + "\n"
+	if buf.String() != want {
+		t.Errorf("unexpected NDJSON:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+// TestParseFormat tests format names.
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"ndjson": NDJSON, "jsonl": NDJSON, "csv": CSV} {
+		if got, err := ParseFormat(name); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %v, %v", name, got, err)
+		}
+	}
+	if _, err := ParseFormat("xlsx"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+// pagedAPI generates search results on demand and checks that every
+// earlier page was written before the next one is requested.
+type pagedAPI struct {
+	t      *testing.T
+	total  int
+	lines  func() int
+	pages  int
+	failAt int
+}
+
+func (pa *pagedAPI) KeywordSearch(ctx context.Context, req jlcpcb.SearchRequest) (*jlcpcb.SearchResponse, error) {
+	pa.pages++
+	start := (req.CurrentPage - 1) * req.PageSize
+	if written := pa.lines(); written != start {
+		pa.t.Errorf("page %d requested with %d products written, want %d", req.CurrentPage, written, start)
+	}
+	if req.CurrentPage == pa.failAt {
+		return nil, jlcpcb.ErrRateLimited{}
+	}
+
+	end := min(start+req.PageSize, pa.total)
+	products := make([]jlcpcb.Product, 0, end-start)
+	for i := start; i < end; i++ {
+		products = append(products, jlcpcb.Product{ComponentCode: fmt.Sprintf("C%d", i+1), StockCount: i})
+	}
+	return &jlcpcb.SearchResponse{Products: products, TotalCount: pa.total, PageSize: req.PageSize, PageNumber: req.CurrentPage}, nil
+}
+
+func (pa *pagedAPI) GetProductDetails(ctx context.Context, code string) (*jlcpcb.Product, error) {
+	return nil, jlcpcb.ErrProductNotFound{ProductCode: code}
+}
+
+// TestSearch tests that a multi-page export streams page by page.
+func TestSearch(t *testing.T) {
+	var buf bytes.Buffer
+	api := &pagedAPI{t: t, total: 25000}
+	api.lines = func() int { return bytes.Count(buf.Bytes(), []byte("\n")) }
+
+	columns, err := ParseColumns([]string{"code", "stock"})
+	if err != nil {
+		t.Fatalf("ParseColumns failed: %v", err)
+	}
+	n, err := Search(context.Background(), api, jlcpcb.SearchRequest{Keyword: "resistor"}, &buf, Options{Format: NDJSON, Columns: columns})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if n != 25000 || api.lines() != 25000 {
+		t.Errorf("expected 25000 products written, got %d and %d lines", n, api.lines())
+	}
+	if api.pages != 250 {
+		t.Errorf("expected 250 pages of 100, got %d", api.pages)
+	}
+	if !strings.HasSuffix(buf.String(), `{"code":"C25000","stock":24999}`+"\n") {
+		t.Errorf("unexpected last line")
+	}
+}
+
+// TestSearchError tests that earlier pages are kept when a page fails.
+func TestSearchError(t *testing.T) {
+	var buf bytes.Buffer
+	api := &pagedAPI{t: t, total: 1000, failAt: 3}
+	api.lines = func() int {
+		if buf.Len() == 0 {
+			return 0
+		}
+		return bytes.Count(buf.Bytes(), []byte("\n")) - 1 // minus the header
+	}
+
+	n, err := Search(context.Background(), api, jlcpcb.SearchRequest{Keyword: "x"}, &buf, Options{Format: CSV})
+	if !errors.Is(err, jlcpcb.ErrRateLimited{}) {
+		t.Fatalf("expected rate limit error, got %v", err)
+	}
+	if n != 200 || api.lines() != 200 {
+		t.Errorf("expected 200 products from earlier pages, got %d and %d rows", n, api.lines())
+	}
+}
+
+// TestSearchFake tests an export against the fake catalog.
+func TestSearchFake(t *testing.T) {
+	fake := jlcpcbtest.NewFake(jlcpcbtest.Fixtures()...)
+
+	var buf bytes.Buffer
+	n, err := Search(context.Background(), fake, jlcpcb.SearchRequest{Keyword: "0603", PageSize: 2}, &buf, Options{Format: CSV})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); n < 3 || lines != n+1 {
+		t.Errorf("expected %d rows plus header, got %d lines", n, lines)
+	}
+}
@@ -0,0 +1,81 @@
+package jlcpcb
+
+import (
+	"context"
+	"sync"
+)
+
+// flightGroup coalesces concurrent calls with the same key into one
+// execution whose result is shared by every caller.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall is an in-flight or completed call.
+type flightCall struct {
+	done    chan struct{}
+	val     interface{}
+	err     error
+	waiters int                // callers still waiting for the result
+	cancel  context.CancelFunc // cancels fn once every caller has gone
+}
+
+// do runs fn once for all concurrent callers with the same key. fn gets a
+// context that keeps the values of the first caller's ctx but is only
+// cancelled when every waiting caller's ctx is done. shared reports whether
+// the result came from a call started by another caller.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (val interface{}, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	call, ok := g.calls[key]
+	if ok {
+		call.waiters++
+	} else {
+		flightCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &flightCall{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		g.calls[key] = call
+		go g.run(flightCtx, key, call, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.val, call.err, ok
+	case <-ctx.Done():
+		g.leave(key, call)
+		return nil, ctx.Err(), ok
+	}
+}
+
+// run executes fn and publishes its result.
+func (g *flightGroup) run(ctx context.Context, key string, call *flightCall, fn func(ctx context.Context) (interface{}, error)) {
+	defer call.cancel()
+
+	call.val, call.err = fn(ctx)
+
+	g.mu.Lock()
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+	g.mu.Unlock()
+	close(call.done)
+}
+
+// leave removes a caller whose context is done. When the last caller
+// leaves, the call is cancelled and forgotten so later callers start afresh.
+func (g *flightGroup) leave(key string, call *flightCall) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+	call.cancel()
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
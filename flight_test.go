@@ -0,0 +1,316 @@
+package jlcpcb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForWaiters blocks until the call for key has n waiting callers.
+func waitForWaiters(t *testing.T, g *flightGroup, key string, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		call, ok := g.calls[key]
+		waiters := 0
+		if ok {
+			waiters = call.waiters
+		}
+		g.mu.Unlock()
+		if waiters >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d callers on %q", n, key)
+}
+
+// TestFlightGroupDo tests that concurrent callers share one execution.
+func TestFlightGroupDo(t *testing.T) {
+	var g flightGroup
+	var runs, sharedCount int32
+	release := make(chan struct{})
+
+	const n = 10
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err, shared := g.do(context.Background(), "key", func(ctx context.Context) (interface{}, error) {
+				atomic.AddInt32(&runs, 1)
+				<-release
+				return "value", nil
+			})
+			if err != nil || v != "value" {
+				t.Errorf("unexpected result %v, %v", v, err)
+			}
+			if shared {
+				atomic.AddInt32(&sharedCount, 1)
+			}
+		}()
+	}
+
+	waitForWaiters(t, &g, "key", n)
+	close(release)
+	wg.Wait()
+
+	if runs != 1 {
+		t.Errorf("expected 1 execution, got %d", runs)
+	}
+	if sharedCount != n-1 {
+		t.Errorf("expected %d shared results, got %d", n-1, sharedCount)
+	}
+	if len(g.calls) != 0 {
+		t.Errorf("expected completed call to be forgotten, got %d", len(g.calls))
+	}
+}
+
+// TestFlightGroupError tests that errors are shared too.
+func TestFlightGroupError(t *testing.T) {
+	var g flightGroup
+	want := errors.New("boom")
+
+	_, err, _ := g.do(context.Background(), "key", func(ctx context.Context) (interface{}, error) {
+		return nil, want
+	})
+	if err != want {
+		t.Errorf("expected %v, got %v", want, err)
+	}
+}
+
+// TestFlightGroupCallerCancel tests that one caller leaving does not cancel the others.
+func TestFlightGroupCallerCancel(t *testing.T) {
+	var g flightGroup
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (interface{}, error) {
+		select {
+		case <-release:
+			return "value", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leaver := make(chan error, 1)
+	go func() {
+		_, err, _ := g.do(ctx, "key", fn)
+		leaver <- err
+	}()
+	waitForWaiters(t, &g, "key", 1)
+
+	stayer := make(chan interface{}, 1)
+	go func() {
+		v, _, _ := g.do(context.Background(), "key", fn)
+		stayer <- v
+	}()
+	waitForWaiters(t, &g, "key", 2)
+
+	cancel()
+	if err := <-leaver; err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	close(release)
+	if v := <-stayer; v != "value" {
+		t.Errorf("expected remaining caller to get the value, got %v", v)
+	}
+}
+
+// TestFlightGroupAllCancel tests that the call is cancelled once every caller leaves.
+func TestFlightGroupAllCancel(t *testing.T) {
+	var g flightGroup
+	cancelled := make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		g.do(ctx, "key", func(ctx context.Context) (interface{}, error) {
+			<-ctx.Done()
+			close(cancelled)
+			return nil, ctx.Err()
+		})
+	}()
+	waitForWaiters(t, &g, "key", 1)
+
+	cancel()
+	<-done
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected abandoned call to be cancelled")
+	}
+
+	v, err, shared := g.do(context.Background(), "key", func(ctx context.Context) (interface{}, error) {
+		return "fresh", nil
+	})
+	if v != "fresh" || err != nil || shared {
+		t.Errorf("expected a fresh call, got %v, %v, shared=%v", v, err, shared)
+	}
+}
+
+// TestClientCoalescesProductLookups tests that concurrent lookups make one HTTP request.
+func TestClientCoalescesProductLookups(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		var wrapper productSearchWrapper
+		wrapper.Code = 200
+		wrapper.Data.ComponentPageInfo = SearchResponse{Products: []Product{sharedProduct()}, TotalCount: 1}
+		_ = json.NewEncoder(w).Encode(wrapper)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(WithBaseURL(server.URL), WithCache(NewMemoryCache()))
+
+	const n = 10
+	products := make([]*Product, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			code := "C1"
+			if i%2 == 1 {
+				code = "c1"
+			}
+			p, err := client.GetProductDetails(context.Background(), code)
+			if err != nil {
+				t.Errorf("GetProductDetails failed: %v", err)
+				return
+			}
+			products[i] = p
+		}(i)
+	}
+
+	waitForWaiters(t, &client.flights, client.getCacheKeyProduct("C1"), n)
+	close(release)
+	wg.Wait()
+
+	if requests != 1 {
+		t.Errorf("expected 1 HTTP request, got %d", requests)
+	}
+
+	// Each caller gets its own copy.
+	mutateProduct(products[0])
+	checkProductUnchanged(t, products[1])
+}
+
+// TestClientCoalescedSearchCopies tests that coalesced searches return
+// independent products.
+func TestClientCoalescedSearchCopies(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		var wrapper productSearchWrapper
+		wrapper.Code = 200
+		wrapper.Data.ComponentPageInfo = SearchResponse{Products: []Product{sharedProduct()}, TotalCount: 1}
+		_ = json.NewEncoder(w).Encode(wrapper)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(WithBaseURL(server.URL))
+	req := SearchRequest{Keyword: "C1"}
+
+	const n = 2
+	responses := make([]*SearchResponse, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := client.KeywordSearch(context.Background(), req)
+			if err != nil {
+				t.Errorf("KeywordSearch failed: %v", err)
+				return
+			}
+			responses[i] = resp
+		}(i)
+	}
+
+	keyReq := req
+	keyReq.CurrentPage, keyReq.PageSize = 1, 50
+	waitForWaiters(t, &client.flights, client.getCacheKeySearch("C1", keyReq), n)
+	close(release)
+	wg.Wait()
+	if responses[0] == nil || responses[1] == nil {
+		t.FailNow()
+	}
+
+	mutateProduct(&responses[0].Products[0])
+	checkProductUnchanged(t, &responses[1].Products[0])
+}
+
+// sharedProduct returns a product with slices and extras to test copying.
+func sharedProduct() Product {
+	return Product{
+		ComponentCode:   "C1",
+		StockCount:      10,
+		ComponentPrices: []PriceBreak{{StartNumber: 1, ProductPrice: MoneyFromFloat(0.5, "")}},
+		Attributes:      []Attribute{{Name: "Resistance", Value: "10kΩ"}},
+		Extras:          map[string]json.RawMessage{"rohsFlag": json.RawMessage("true")},
+	}
+}
+
+// mutateProduct changes every shared field of a product from sharedProduct.
+func mutateProduct(p *Product) {
+	p.StockCount = 0
+	p.ComponentPrices[0].StartNumber = 99
+	p.Attributes[0].Value = "changed"
+	p.Extras["rohsFlag"][0] = 'x'
+	p.Extras["added"] = json.RawMessage("1")
+}
+
+// checkProductUnchanged checks that a product still matches sharedProduct.
+func checkProductUnchanged(t *testing.T, p *Product) {
+	t.Helper()
+	if p.StockCount != 10 || p.ComponentPrices[0].StartNumber != 1 || p.Attributes[0].Value != "10kΩ" ||
+		string(p.Extras["rohsFlag"]) != "true" || len(p.Extras) != 1 {
+		t.Errorf("expected callers not to share product data, got %+v", p)
+	}
+}
+
+// TestGetCacheKeySearchFilters tests that filters are part of the search cache key.
+func TestGetCacheKeySearchFilters(t *testing.T) {
+	client := NewClient()
+	base := SearchRequest{CurrentPage: 1, PageSize: 50}
+
+	plain := client.getCacheKeySearch("stm32", base)
+	if plain != "search:USD:stm32:1:50" {
+		t.Errorf("unexpected key without filters: %s", plain)
+	}
+
+	filtered := base
+	filtered.Brands = []string{"ST", "GigaDevice"}
+	reordered := base
+	reordered.Brands = []string{"GigaDevice", "ST"}
+	stock := base
+	stock.StockOnly = true
+	preferred := base
+	preferred.PreferredOnly = true
+
+	if client.getCacheKeySearch("stm32", filtered) == plain {
+		t.Error("expected brand filter to change the key")
+	}
+	if client.getCacheKeySearch("stm32", filtered) != client.getCacheKeySearch("stm32", reordered) {
+		t.Error("expected brand order not to matter")
+	}
+	if client.getCacheKeySearch("stm32", stock) == plain {
+		t.Error("expected stock filter to change the key")
+	}
+	if client.getCacheKeySearch("stm32", preferred) == plain {
+		t.Error("expected preferred filter to change the key")
+	}
+}
@@ -0,0 +1,39 @@
+//go:build !unix
+
+package jlcpcb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+)
+
+const (
+	lockRetryInterval = time.Millisecond // how often a contended lock is retried
+	staleLockAge      = 10 * time.Second // lock files older than this are abandoned
+)
+
+// lockFile takes an exclusive lock on f by creating a companion lock file,
+// polling until it is acquired or ctx is done. Lock files left behind by a
+// crashed process are removed once they are older than staleLockAge.
+func lockFile(ctx context.Context, f *os.File) (func(), error) {
+	lockPath := f.Name() + ".lock"
+	for {
+		lf, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			_ = lf.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if err := sleep(ctx, lockRetryInterval); err != nil {
+			return nil, err
+		}
+	}
+}
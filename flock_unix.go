@@ -0,0 +1,31 @@
+//go:build unix
+
+package jlcpcb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"time"
+)
+
+// lockRetryInterval is how often a contended lock is retried.
+const lockRetryInterval = time.Millisecond
+
+// lockFile takes an exclusive flock on f, polling until it is acquired or
+// ctx is done.
+func lockFile(ctx context.Context, f *os.File) (func(), error) {
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			return func() { _ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN) }, nil
+		}
+		if !errors.Is(err, syscall.EWOULDBLOCK) && !errors.Is(err, syscall.EINTR) {
+			return nil, err
+		}
+		if err := sleep(ctx, lockRetryInterval); err != nil {
+			return nil, err
+		}
+	}
+}
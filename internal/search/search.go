@@ -0,0 +1,213 @@
+// Package search implements the keyword matching, filtering and paging
+// rules shared by the offline and fake JLCPCB backends.
+package search
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+// Normalize validates a search request and applies the same defaults as
+// jlcpcb.Client.KeywordSearch.
+func Normalize(req *jlcpcb.SearchRequest) error {
+	req.Keyword = strings.TrimSpace(req.Keyword)
+	if req.Keyword == "" {
+		return fmt.Errorf("keyword is required")
+	}
+
+	if req.CurrentPage <= 0 {
+		req.CurrentPage = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 50
+	}
+	if req.PageSize > 100 {
+		req.PageSize = 100
+	}
+	return nil
+}
+
+// PageBounds returns the slice bounds of the requested page within n results.
+func PageBounds(n, page, pageSize int) (start, end int) {
+	start = (page - 1) * pageSize
+	if start > n {
+		start = n
+	}
+	end = start + pageSize
+	if end > n {
+		end = n
+	}
+	return start, end
+}
+
+// MatchesKeyword reports whether every keyword token prefix-matches a token
+// of the product, or the keyword is the product's part code.
+func MatchesKeyword(p *jlcpcb.Product, keyword string) bool {
+	if NormalizeCode(keyword) == NormalizeCode(p.ComponentCode) {
+		return true
+	}
+
+	query := Tokenize(keyword)
+	if len(query) == 0 {
+		return false
+	}
+
+	tokens := ProductTokens(p)
+	for _, q := range query {
+		found := false
+		for _, tok := range tokens {
+			if strings.HasPrefix(tok, q) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesFilters reports whether a product satisfies the non-keyword
+// filters of a search request.
+func MatchesFilters(p *jlcpcb.Product, req *jlcpcb.SearchRequest) bool {
+	if req.StockOnly && p.StockCount <= 0 {
+		return false
+	}
+	if req.ComponentType != "" && !strings.EqualFold(p.ComponentLibraryType, req.ComponentType) {
+		return false
+	}
+	if req.PreferredOnly && !p.IsPreferred() {
+		return false
+	}
+	if !matchesPresale(p, req.PresaleType) {
+		return false
+	}
+	if req.SortBy != "" && !strings.EqualFold(p.FirstSortName, req.SortBy) {
+		return false
+	}
+	if req.SortBySecondary != "" && !strings.EqualFold(p.SecondSortName, req.SortBySecondary) {
+		return false
+	}
+	if len(req.Brands) > 0 && !containsFold(req.Brands, p.ComponentBrandEn) {
+		return false
+	}
+	if len(req.Packages) > 0 && !containsFold(req.Packages, p.ComponentSpecificationEn) {
+		return false
+	}
+	for _, filter := range req.Attributes {
+		if !hasAttribute(p, filter) {
+			return false
+		}
+	}
+	return true
+}
+
+// ProductTokens returns the distinct searchable tokens of a product.
+func ProductTokens(p *jlcpcb.Product) []string {
+	fields := []string{
+		p.ComponentCode,
+		p.ComponentModelEn,
+		p.ComponentBrandEn,
+		p.ComponentTypeEn,
+		p.ComponentName,
+		p.ComponentSpecificationEn,
+		p.Describe,
+		p.FirstSortName,
+		p.SecondSortName,
+	}
+	for _, attr := range p.Attributes {
+		fields = append(fields, attr.Value)
+	}
+
+	seen := make(map[string]bool)
+	var tokens []string
+	for _, field := range fields {
+		for _, tok := range Tokenize(field) {
+			if !seen[tok] {
+				seen[tok] = true
+				tokens = append(tokens, tok)
+			}
+		}
+	}
+	return tokens
+}
+
+// Tokenize splits text into lowercase search tokens. Hyphenated words are
+// indexed both whole and by their parts, so "MPM3506AGQV-Z" matches both
+// "mpm3506agqv-z" and "mpm3506agqv".
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isTokenRune(r)
+	})
+
+	var tokens []string
+	for _, w := range words {
+		w = strings.Trim(w, ".-")
+		if w == "" {
+			continue
+		}
+		tokens = append(tokens, w)
+		if strings.Contains(w, "-") {
+			for _, part := range strings.Split(w, "-") {
+				if part != "" {
+					tokens = append(tokens, part)
+				}
+			}
+		}
+	}
+	return tokens
+}
+
+// NormalizeCode normalizes a JLCPCB part code for lookups.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// isTokenRune reports whether r is part of a search token.
+func isTokenRune(r rune) bool {
+	switch r {
+	case '.', '-', '+', '%', '±':
+		return true
+	default:
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+}
+
+// matchesPresale reports whether a product is offered with a presale type.
+// Every part is listed under "stock"; "buy" lists the parts JLCPCB can buy
+// in through global sourcing, and "post" those of them that are out of
+// stock and so can only be pre-ordered.
+func matchesPresale(p *jlcpcb.Product, presaleType string) bool {
+	switch strings.ToLower(strings.TrimSpace(presaleType)) {
+	case "buy":
+		return p.IsBuyable()
+	case "post":
+		return p.IsBuyable() && p.StockCount <= 0
+	default:
+		return true
+	}
+}
+
+// hasAttribute reports whether the product has an attribute matching filter.
+func hasAttribute(p *jlcpcb.Product, filter jlcpcb.FilterAttribute) bool {
+	for _, attr := range p.Attributes {
+		if strings.EqualFold(attr.Name, filter.Name) && strings.EqualFold(strings.TrimSpace(attr.Value), strings.TrimSpace(filter.Value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), strings.TrimSpace(s)) {
+			return true
+		}
+	}
+	return false
+}
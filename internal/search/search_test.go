@@ -0,0 +1,134 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+// TestTokenize tests search token extraction.
+func TestTokenize(t *testing.T) {
+	got := Tokenize("MPM3506AGQV-Z, 10kΩ ±1% (0402)")
+	want := []string{"mpm3506agqv-z", "mpm3506agqv", "z", "10kω", "±1%", "0402"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestNormalize tests request validation and defaults.
+func TestNormalize(t *testing.T) {
+	req := jlcpcb.SearchRequest{Keyword: "  led ", PageSize: 500}
+	if err := Normalize(&req); err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	if req.Keyword != "led" || req.CurrentPage != 1 || req.PageSize != 100 {
+		t.Errorf("unexpected normalized request: %+v", req)
+	}
+
+	req = jlcpcb.SearchRequest{}
+	if err := Normalize(&req); err == nil {
+		t.Error("expected error for empty keyword")
+	}
+	if req.PageSize != 0 {
+		t.Error("expected invalid request to be left unpaged")
+	}
+}
+
+// TestPageBounds tests page slicing.
+func TestPageBounds(t *testing.T) {
+	tests := []struct {
+		n, page, size, start, end int
+	}{
+		{10, 1, 4, 0, 4},
+		{10, 3, 4, 8, 10},
+		{10, 4, 4, 10, 10},
+		{0, 1, 50, 0, 0},
+	}
+
+	for _, test := range tests {
+		start, end := PageBounds(test.n, test.page, test.size)
+		if start != test.start || end != test.end {
+			t.Errorf("PageBounds(%d, %d, %d) = %d, %d; expected %d, %d",
+				test.n, test.page, test.size, start, end, test.start, test.end)
+		}
+	}
+}
+
+// TestMatchesKeyword tests keyword matching.
+func TestMatchesKeyword(t *testing.T) {
+	p := &jlcpcb.Product{
+		ComponentCode:            "C5676715",
+		ComponentModelEn:         "MPM3506AGQV-Z",
+		ComponentSpecificationEn: "QFN-19(3x5)",
+	}
+
+	for _, kw := range []string{"c5676715", "MPM3506", "mpm3506agqv-z qfn", "qfn-19"} {
+		if !MatchesKeyword(p, kw) {
+			t.Errorf("expected %q to match", kw)
+		}
+	}
+	for _, kw := range []string{"MPM3506 0402", "3506", ""} {
+		if MatchesKeyword(p, kw) {
+			t.Errorf("expected %q not to match", kw)
+		}
+	}
+}
+
+// TestMatchesFilters tests non-keyword filters.
+func TestMatchesFilters(t *testing.T) {
+	p := &jlcpcb.Product{
+		ComponentBrandEn:         "Samsung Electro-Mechanics",
+		ComponentSpecificationEn: "0402",
+		FirstSortName:            "Capacitors",
+		StockCount:               10,
+		Attributes:               []jlcpcb.Attribute{{Name: "Capacitance", Value: "100nF"}},
+		ComponentLibraryType:     "base",
+	}
+
+	pass := []jlcpcb.SearchRequest{
+		{},
+		{StockOnly: true, Packages: []string{"0603", "0402"}},
+		{Brands: []string{"samsung electro-mechanics"}, SortBy: "capacitors"},
+		{Attributes: []jlcpcb.FilterAttribute{{Name: "Capacitance", Value: "100nF"}}},
+		{ComponentType: "Base"},
+		{PresaleType: "stock"},
+	}
+	fail := []jlcpcb.SearchRequest{
+		{Packages: []string{"0603"}},
+		{Brands: []string{"Murata"}},
+		{SortBy: "Resistors"},
+		{SortBySecondary: "MLCC"},
+		{Attributes: []jlcpcb.FilterAttribute{{Name: "Capacitance", Value: "1uF"}}},
+		{ComponentType: "expand"},
+		{PresaleType: "buy"},
+		{PresaleType: "post"},
+		{PreferredOnly: true},
+	}
+
+	for i := range pass {
+		if !MatchesFilters(p, &pass[i]) {
+			t.Errorf("expected filters %+v to match", pass[i])
+		}
+	}
+	for i := range fail {
+		if MatchesFilters(p, &fail[i]) {
+			t.Errorf("expected filters %+v not to match", fail[i])
+		}
+	}
+
+	p.StockCount = 0
+	if MatchesFilters(p, &jlcpcb.SearchRequest{StockOnly: true}) {
+		t.Error("expected out-of-stock product to be filtered")
+	}
+
+	p.IsBuyComponent = "1"
+	if !MatchesFilters(p, &jlcpcb.SearchRequest{PresaleType: "buy"}) || !MatchesFilters(p, &jlcpcb.SearchRequest{PresaleType: "post"}) {
+		t.Error("expected an out-of-stock buyable product to be offered for global sourcing and pre-order")
+	}
+	p.StockCount = 10
+	if MatchesFilters(p, &jlcpcb.SearchRequest{PresaleType: "post"}) {
+		t.Error("expected an in-stock product not to need a pre-order")
+	}
+}
@@ -0,0 +1,87 @@
+// Package units parses component values written with SI prefixes, as
+// found in schematics, BOMs and JLCPCB part attributes.
+package units
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// prefixes maps SI prefix characters to multipliers.
+var prefixes = map[rune]float64{
+	'p': 1e-12, 'n': 1e-9, 'u': 1e-6, 'µ': 1e-6, 'μ': 1e-6, 'm': 1e-3,
+	'k': 1e3, 'K': 1e3, 'M': 1e6, 'G': 1e9,
+}
+
+// Parse parses a value such as "10k", "4k7", "100nF", "0.1uF", "2R2",
+// "10kΩ", "16V" or "62.5mW" into a number in base units and its unit.
+// Units are returned upper case, with ohm spellings as "Ω"; a bare R, as in
+// "10R", is returned as "R". Text after the first space, slash or comma is
+// ignored. ok is false if value does not start with a number.
+func Parse(value string) (float64, string, bool) {
+	s := strings.TrimSpace(value)
+	if i := strings.IndexAny(s, " /,"); i > 0 {
+		s = s[:i]
+	}
+	runes := []rune(s)
+
+	i := 0
+	for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+		i++
+	}
+	if i == 0 {
+		return 0, "", false
+	}
+	number := string(runes[:i])
+	rest := runes[i:]
+
+	multiplier := 1.0
+	if len(rest) > 0 {
+		if m, ok := prefixes[rest[0]]; ok {
+			multiplier = m
+			rest = rest[1:]
+		} else if (rest[0] == 'R' || rest[0] == 'r') && len(rest) > 1 && unicode.IsDigit(rest[1]) {
+			// "2R2" style: R marks the decimal point.
+			rest = rest[1:]
+			number, rest = appendDecimals(number, rest)
+		}
+	}
+
+	// "4k7" style: digits after the prefix are decimals.
+	if multiplier != 1 && !strings.Contains(number, ".") {
+		number, rest = appendDecimals(number, rest)
+	}
+
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, "", false
+	}
+
+	unit := strings.TrimSpace(string(rest))
+	switch strings.ToLower(unit) {
+	case "ω", "ohm", "ohms":
+		unit = "Ω"
+	default:
+		unit = strings.ToUpper(unit)
+	}
+	return n * multiplier, unit, true
+}
+
+// appendDecimals moves leading digits of rest after a decimal point.
+func appendDecimals(number string, rest []rune) (string, []rune) {
+	j := 0
+	for j < len(rest) && unicode.IsDigit(rest[j]) {
+		j++
+	}
+	if j == 0 {
+		return number, rest
+	}
+	return number + "." + string(rest[:j]), rest[j:]
+}
+
+// Equal compares values with a relative tolerance for float error.
+func Equal(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(math.Abs(a), math.Abs(b))
+}
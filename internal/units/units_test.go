@@ -0,0 +1,38 @@
+package units
+
+import "testing"
+
+// TestParse tests SI values with and without units.
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		value float64
+		unit  string
+		ok    bool
+	}{
+		{"10k", 10e3, "", true},
+		{"4k7", 4.7e3, "", true},
+		{"2R2", 2.2, "", true},
+		{"10R", 10, "R", true},
+		{"10kΩ", 10e3, "Ω", true},
+		{"1 ohm", 1, "", true},
+		{"1ohm", 1, "Ω", true},
+		{"100nF", 100e-9, "F", true},
+		{"0.1uf", 100e-9, "F", true},
+		{"4.7µH", 4.7e-6, "H", true},
+		{"16V", 16, "V", true},
+		{"62.5mW", 62.5e-3, "W", true},
+		{"1M", 1e6, "", true},
+		{"1.5.2k", 0, "", false},
+		{"LM358", 0, "", false},
+		{"±1%", 0, "", false},
+		{"", 0, "", false},
+	}
+
+	for _, tt := range tests {
+		value, unit, ok := Parse(tt.input)
+		if ok != tt.ok || unit != tt.unit || (ok && !Equal(value, tt.value)) {
+			t.Errorf("Parse(%q) = %v, %q, %v; want %v, %q, %v", tt.input, value, unit, ok, tt.value, tt.unit, tt.ok)
+		}
+	}
+}
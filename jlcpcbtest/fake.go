@@ -0,0 +1,156 @@
+package jlcpcbtest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+	"github.com/PatrickWalther/go-jlcpcb-parts/internal/search"
+)
+
+var _ jlcpcb.PartsAPI = (*Fake)(nil)
+
+// Fake is an in-memory jlcpcb.PartsAPI. It applies the same keyword,
+// filter and pagination rules as catalog.LocalClient and records every
+// call for later inspection. It is safe for concurrent use.
+type Fake struct {
+	mu       sync.Mutex
+	products []jlcpcb.Product
+	searches []jlcpcb.SearchRequest
+	lookups  []string
+	err      error
+}
+
+// NewFake creates a fake backend seeded with the given products.
+func NewFake(products ...jlcpcb.Product) *Fake {
+	f := &Fake{}
+	f.Add(products...)
+	return f
+}
+
+// Add inserts or replaces products, keyed by component code.
+func (f *Fake) Add(products ...jlcpcb.Product) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, p := range products {
+		replaced := false
+		for i := range f.products {
+			if search.NormalizeCode(f.products[i].ComponentCode) == search.NormalizeCode(p.ComponentCode) {
+				f.products[i] = p
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			f.products = append(f.products, p)
+		}
+	}
+}
+
+// SetError makes every subsequent call fail with err. Pass nil to clear it.
+func (f *Fake) SetError(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.err = err
+}
+
+// Searches returns the requests passed to KeywordSearch, in call order.
+func (f *Fake) Searches() []jlcpcb.SearchRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]jlcpcb.SearchRequest(nil), f.searches...)
+}
+
+// Lookups returns the part codes passed to GetProductDetails, in call order.
+func (f *Fake) Lookups() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.lookups...)
+}
+
+// KeywordSearch searches the seeded products by keyword with optional filters.
+func (f *Fake) KeywordSearch(ctx context.Context, req jlcpcb.SearchRequest) (*jlcpcb.SearchResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.searches = append(f.searches, req)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	if err := search.Normalize(&req); err != nil {
+		return nil, err
+	}
+
+	matched := Filter(f.products, req)
+	start, end := search.PageBounds(len(matched), req.CurrentPage, req.PageSize)
+
+	return &jlcpcb.SearchResponse{
+		Products:   append([]jlcpcb.Product{}, matched[start:end]...),
+		TotalCount: len(matched),
+		PageSize:   req.PageSize,
+		PageNumber: req.CurrentPage,
+	}, nil
+}
+
+// GetProductDetails retrieves a seeded product by part code.
+func (f *Fake) GetProductDetails(ctx context.Context, partCode string) (*jlcpcb.Product, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lookups = append(f.lookups, partCode)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	partCode = strings.TrimSpace(partCode)
+	if partCode == "" {
+		return nil, fmt.Errorf("part code is required")
+	}
+
+	for i := range f.products {
+		if search.NormalizeCode(f.products[i].ComponentCode) == search.NormalizeCode(partCode) {
+			product := f.products[i]
+			return &product, nil
+		}
+	}
+	return nil, jlcpcb.ErrProductNotFound{ProductCode: partCode}
+}
+
+// Filter returns the products matching the keyword and filters of req, in
+// their original order. An exact part code match is returned alone, and an
+// empty keyword matches every product. PresaleType "buy" keeps the products
+// flagged IsBuyComponent, and "post" those of them that are out of stock.
+func Filter(products []jlcpcb.Product, req jlcpcb.SearchRequest) []jlcpcb.Product {
+	keyword := strings.TrimSpace(req.Keyword)
+	for i := range products {
+		if keyword != "" && search.NormalizeCode(products[i].ComponentCode) == search.NormalizeCode(keyword) {
+			if search.MatchesFilters(&products[i], &req) {
+				return []jlcpcb.Product{products[i]}
+			}
+			return nil
+		}
+	}
+
+	var matched []jlcpcb.Product
+	for i := range products {
+		if keyword != "" && !search.MatchesKeyword(&products[i], keyword) {
+			continue
+		}
+		if search.MatchesFilters(&products[i], &req) {
+			matched = append(matched, products[i])
+		}
+	}
+	return matched
+}
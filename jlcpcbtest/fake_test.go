@@ -0,0 +1,115 @@
+package jlcpcbtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+// TestFakeKeywordSearch tests keyword search with filters.
+func TestFakeKeywordSearch(t *testing.T) {
+	fake := NewFake(Fixtures()...)
+	ctx := context.Background()
+
+	resp, err := fake.KeywordSearch(ctx, jlcpcb.SearchRequest{Keyword: "100nF"})
+	if err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+	if resp.TotalCount != 2 {
+		t.Errorf("expected 2 capacitors, got %d", resp.TotalCount)
+	}
+
+	resp, err = fake.KeywordSearch(ctx, jlcpcb.SearchRequest{Keyword: "100nF", Packages: []string{"0603"}})
+	if err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+	if resp.TotalCount != 1 || resp.Products[0].ComponentCode != "C14663" {
+		t.Errorf("expected C14663, got %+v", resp.Products)
+	}
+
+	resp, err = fake.KeywordSearch(ctx, jlcpcb.SearchRequest{Keyword: "microcontrollers", StockOnly: true})
+	if err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+	if resp.TotalCount != 1 || resp.Products[0].ComponentCode != "C8734" {
+		t.Errorf("expected only the in-stock MCU, got %+v", resp.Products)
+	}
+}
+
+// TestFakeKeywordSearchPagination tests paging through results.
+func TestFakeKeywordSearchPagination(t *testing.T) {
+	fake := NewFake(Fixtures()...)
+
+	resp, err := fake.KeywordSearch(context.Background(), jlcpcb.SearchRequest{
+		Keyword:     "0603",
+		CurrentPage: 2,
+		PageSize:    2,
+	})
+	if err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+
+	if resp.TotalCount != 4 || len(resp.Products) != 2 || resp.PageNumber != 2 {
+		t.Errorf("unexpected page: total %d, len %d, page %d", resp.TotalCount, len(resp.Products), resp.PageNumber)
+	}
+}
+
+// TestFakeGetProductDetails tests lookup by part code.
+func TestFakeGetProductDetails(t *testing.T) {
+	fake := NewFake(Fixtures()...)
+	ctx := context.Background()
+
+	p, err := fake.GetProductDetails(ctx, "c8734")
+	if err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+	if p.ComponentModelEn != "STM32F103C8T6" {
+		t.Errorf("unexpected product %s", p.ComponentModelEn)
+	}
+
+	var notFound jlcpcb.ErrProductNotFound
+	if _, err := fake.GetProductDetails(ctx, "C1"); !errors.As(err, &notFound) {
+		t.Errorf("expected ErrProductNotFound, got %v", err)
+	}
+
+	if got := fake.Lookups(); len(got) != 2 || got[0] != "c8734" {
+		t.Errorf("unexpected recorded lookups: %v", got)
+	}
+}
+
+// TestFakeSetError tests error injection.
+func TestFakeSetError(t *testing.T) {
+	fake := NewFake(Fixtures()...)
+	ctx := context.Background()
+	injected := errors.New("boom")
+
+	fake.SetError(injected)
+	if _, err := fake.KeywordSearch(ctx, jlcpcb.SearchRequest{Keyword: "led"}); !errors.Is(err, injected) {
+		t.Errorf("expected injected error, got %v", err)
+	}
+
+	fake.SetError(nil)
+	if _, err := fake.KeywordSearch(ctx, jlcpcb.SearchRequest{Keyword: "led"}); err != nil {
+		t.Errorf("expected error to be cleared, got %v", err)
+	}
+
+	if got := fake.Searches(); len(got) != 2 {
+		t.Errorf("expected 2 recorded searches, got %d", len(got))
+	}
+}
+
+// TestFakeAddReplaces tests that Add replaces products with the same code.
+func TestFakeAddReplaces(t *testing.T) {
+	fake := NewFake(jlcpcb.Product{ComponentCode: "C1", StockCount: 1})
+	fake.Add(jlcpcb.Product{ComponentCode: "C1", StockCount: 2})
+
+	p, err := fake.GetProductDetails(context.Background(), "C1")
+	if err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+	if p.StockCount != 2 {
+		t.Errorf("expected replaced product, got stock %d", p.StockCount)
+	}
+}
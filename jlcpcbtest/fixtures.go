@@ -0,0 +1,60 @@
+// Package jlcpcbtest provides test doubles for code that uses the JLCPCB
+// parts client.
+//
+// Fake is an in-memory jlcpcb.PartsAPI for unit tests, and Server is an
+// httptest server speaking the JLCPCB search API for end-to-end tests of
+// the real client. Both are seeded from fixtures: either the built-in set
+// returned by Fixtures or JSON files loaded with LoadFixtures.
+package jlcpcbtest
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+//go:embed products.json
+var defaultFixtures []byte
+
+// Fixtures returns a built-in set of common parts: chip resistors and
+// capacitors, an LED, an LDO, microcontrollers and a power module.
+func Fixtures() []jlcpcb.Product {
+	products, err := ParseFixtures(defaultFixtures)
+	if err != nil {
+		panic(fmt.Sprintf("jlcpcbtest: invalid built-in fixtures: %v", err))
+	}
+	return products
+}
+
+// LoadFixtures reads products from a JSON fixture file.
+// See ParseFixtures for the accepted formats.
+func LoadFixtures(path string) ([]jlcpcb.Product, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+	return ParseFixtures(data)
+}
+
+// ParseFixtures decodes products from JSON. It accepts either an array of
+// products or a raw selectSmtComponentList/v2 API response, so captured
+// responses can be used as fixtures unchanged.
+func ParseFixtures(data []byte) ([]jlcpcb.Product, error) {
+	var products []jlcpcb.Product
+	if err := json.Unmarshal(data, &products); err == nil {
+		return products, nil
+	}
+
+	var wrapper struct {
+		Data struct {
+			ComponentPageInfo jlcpcb.SearchResponse `json:"componentPageInfo"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures: %w", err)
+	}
+	return wrapper.Data.ComponentPageInfo.Products, nil
+}
@@ -0,0 +1,68 @@
+package jlcpcbtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestFixtures tests that the built-in fixtures decode.
+func TestFixtures(t *testing.T) {
+	products := Fixtures()
+
+	if len(products) < 5 {
+		t.Fatalf("expected built-in fixtures, got %d products", len(products))
+	}
+	for _, p := range products {
+		if p.ComponentCode == "" || len(p.ComponentPrices) == 0 {
+			t.Errorf("incomplete fixture: %+v", p)
+		}
+	}
+}
+
+// TestFixturesExtendedFields tests library metadata and unmapped fields in the fixtures.
+func TestFixturesExtendedFields(t *testing.T) {
+	byCode := make(map[string]int)
+	products := Fixtures()
+	for i, p := range products {
+		byCode[p.ComponentCode] = i
+	}
+
+	resistor := products[byCode["C25744"]]
+	if !resistor.IsBasic() || resistor.AssemblyProcess != "SMT" {
+		t.Errorf("expected basic SMT resistor, got %+v", resistor)
+	}
+
+	rp2040 := products[byCode["C2040"]]
+	if rp2040.IsBasic() || !rp2040.IsPreferred() {
+		t.Errorf("expected preferred extended RP2040, got library %q preferred %v",
+			rp2040.ComponentLibraryType, rp2040.PreferredComponentFlag)
+	}
+	if string(rp2040.Extras["rohsFlag"]) != "true" {
+		t.Errorf("expected rohsFlag in extras, got %v", rp2040.Extras)
+	}
+}
+
+// TestLoadFixturesAPIResponse tests loading a captured API response.
+func TestLoadFixturesAPIResponse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "response.json")
+	body := `{"code":200,"message":null,"data":{"componentPageInfo":{"list":[{"componentCode":"C1"},{"componentCode":"C2"}],"total":2}}}`
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	products, err := LoadFixtures(path)
+	if err != nil {
+		t.Fatalf("LoadFixtures failed: %v", err)
+	}
+	if len(products) != 2 || products[1].ComponentCode != "C2" {
+		t.Errorf("unexpected products: %+v", products)
+	}
+}
+
+// TestParseFixturesInvalid tests that invalid JSON is rejected.
+func TestParseFixturesInvalid(t *testing.T) {
+	if _, err := ParseFixtures([]byte(`{invalid`)); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+}
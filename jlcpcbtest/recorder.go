@@ -0,0 +1,349 @@
+package jlcpcbtest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode selects whether a Recorder captures or replays interactions.
+type Mode int
+
+const (
+	// ModeRecord forwards requests to the real transport and records them.
+	ModeRecord Mode = iota
+	// ModeReplay answers requests from the cassette and fails on unmatched requests.
+	ModeReplay
+	// ModeAuto replays if the cassette exists and records otherwise.
+	ModeAuto
+)
+
+// DefaultScrubHeaders are response headers that change between runs and
+// are removed from recorded interactions.
+var DefaultScrubHeaders = []string{
+	"Date",
+	"Set-Cookie",
+	"Expires",
+	"Age",
+	"Etag",
+	"Last-Modified",
+	"Server-Timing",
+	"X-Request-Id",
+	"X-Trace-Id",
+	"Cf-Ray",
+	"Content-Length",
+}
+
+// RecorderConfig contains recorder configuration.
+type RecorderConfig struct {
+	Mode         Mode
+	Transport    http.RoundTripper // Real transport for recording (default: http.DefaultTransport)
+	ScrubHeaders []string          // Response headers to drop (default: DefaultScrubHeaders)
+}
+
+// Interaction is a recorded request/response pair.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest identifies a request by method, path, query and body.
+type RecordedRequest struct {
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Query      string `json:"query,omitempty"`
+	Body       string `json:"body,omitempty"`        // Normalized JSON, or the raw body
+	BodyBase64 string `json:"body_base64,omitempty"` // Base64 body if it is not valid UTF-8
+}
+
+// RecordedResponse is a recorded response with a decompressed body. Bodies
+// that are not valid UTF-8, such as PDFs and images, are stored in
+// BodyBase64 so they survive the JSON cassette unchanged.
+type RecordedResponse struct {
+	Status     int                 `json:"status"`
+	Headers    map[string][]string `json:"headers,omitempty"`
+	Body       string              `json:"body"`
+	BodyBase64 string              `json:"body_base64,omitempty"`
+}
+
+// cassette is the on-disk format of a recording.
+type cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// ErrUnmatchedRequest indicates a replayed request has no recorded interaction.
+type ErrUnmatchedRequest struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+func (e ErrUnmatchedRequest) Error() string {
+	return fmt.Sprintf("no recorded interaction for %s %s %s", e.Method, e.Path, e.Body)
+}
+
+// Recorder is an http.RoundTripper that records interactions to a cassette
+// file or replays them from it. Requests are matched on method, path, query
+// and JSON body with object keys normalized, so the base URL and field order
+// do not matter. Use it with jlcpcb.WithHTTPClient(recorder.Client()).
+type Recorder struct {
+	mode      Mode
+	path      string
+	transport http.RoundTripper
+	scrub     []string
+
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     map[int]bool
+}
+
+// NewRecorder creates a recorder for the cassette at path. In replay mode
+// the cassette must exist.
+func NewRecorder(path string, cfg RecorderConfig) (*Recorder, error) {
+	r := &Recorder{
+		mode:      cfg.Mode,
+		path:      path,
+		transport: cfg.Transport,
+		scrub:     cfg.ScrubHeaders,
+		replayed:  make(map[int]bool),
+	}
+	if r.transport == nil {
+		r.transport = http.DefaultTransport
+	}
+	if r.scrub == nil {
+		r.scrub = DefaultScrubHeaders
+	}
+
+	if r.mode == ModeAuto {
+		r.mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		}
+	}
+
+	if r.mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cassette: %w", err)
+		}
+		var c cassette
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("failed to parse cassette: %w", err)
+		}
+		r.interactions = c.Interactions
+	}
+
+	return r, nil
+}
+
+// Mode returns the effective mode of the recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an HTTP client that uses the recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns the recorded or loaded interactions.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save writes the recorded interactions to the cassette file. It does
+// nothing in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode == ModeReplay {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(cassette{Interactions: r.interactions}, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode cassette: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	recorded := RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query().Encode(),
+	}
+	if utf8.Valid(body) {
+		recorded.Body = normalizeBody(body)
+	} else {
+		recorded.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+	return r.record(req, body, recorded)
+}
+
+// replay answers a request from the cassette. Matching interactions are
+// served in recorded order; once all are used the last one is repeated.
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+	for i, in := range r.interactions {
+		if in.Request != recorded {
+			continue
+		}
+		last = i
+		if !r.replayed[i] {
+			r.replayed[i] = true
+			return in.Response.httpResponse(req)
+		}
+	}
+	if last >= 0 {
+		return r.interactions[last].Response.httpResponse(req)
+	}
+
+	return nil, ErrUnmatchedRequest{Method: recorded.Method, Path: recorded.Path, Body: recorded.Body}
+}
+
+// record forwards a request to the real transport and records the result.
+func (r *Recorder) record(req *http.Request, body []byte, recorded RecordedRequest) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.ContentLength = int64(len(body))
+	}
+
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := resp.Header.Clone()
+	headers.Del("Content-Encoding")
+	for _, h := range r.scrub {
+		headers.Del(h)
+	}
+
+	response := RecordedResponse{
+		Status:  resp.StatusCode,
+		Headers: headers,
+	}
+	if utf8.Valid(respBody) {
+		response.Body = string(respBody)
+	} else {
+		response.BodyBase64 = base64.StdEncoding.EncodeToString(respBody)
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, Interaction{Request: recorded, Response: response})
+	r.mu.Unlock()
+
+	return response.httpResponse(req)
+}
+
+// httpResponse builds an http.Response for req from the recording.
+func (rr RecordedResponse) httpResponse(req *http.Request) (*http.Response, error) {
+	header := http.Header{}
+	for k, v := range rr.Headers {
+		header[k] = append([]string(nil), v...)
+	}
+
+	body := []byte(rr.Body)
+	if rr.BodyBase64 != "" {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(rr.BodyBase64); err != nil {
+			return nil, fmt.Errorf("failed to decode recorded body: %w", err)
+		}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rr.Status, http.StatusText(rr.Status)),
+		StatusCode:    rr.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// readBody reads a response body, decompressing gzip content.
+func readBody(resp *http.Response) ([]byte, error) {
+	var reader io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return data, nil
+}
+
+// normalizeBody re-encodes JSON bodies with sorted keys and no whitespace.
+// Other bodies are returned unchanged.
+func normalizeBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return string(body)
+	}
+
+	normalized, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+	return string(normalized)
+}
@@ -0,0 +1,226 @@
+package jlcpcbtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+// recordCassette records a search and product lookup against a fake server.
+func recordCassette(t *testing.T, path string) {
+	t.Helper()
+
+	server := NewServer(Fixtures())
+	defer server.Close()
+
+	rec, err := NewRecorder(path, RecorderConfig{Mode: ModeRecord})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+
+	client := jlcpcb.NewClient(jlcpcb.WithBaseURL(server.URL), jlcpcb.WithHTTPClient(rec.Client()))
+	ctx := context.Background()
+
+	if _, err := client.KeywordSearch(ctx, jlcpcb.SearchRequest{Keyword: "100nF", PageSize: 10}); err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+	if _, err := client.GetProductDetails(ctx, "C8734"); err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+}
+
+// TestRecorderRecordAndReplay tests replaying a recorded cassette offline.
+func TestRecorderRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "search.json")
+	recordCassette(t, path)
+
+	rec, err := NewRecorder(path, RecorderConfig{Mode: ModeReplay})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	if len(rec.Interactions()) != 2 {
+		t.Fatalf("expected 2 interactions, got %d", len(rec.Interactions()))
+	}
+
+	// The server is gone, so responses must come from the cassette.
+	client := jlcpcb.NewClient(jlcpcb.WithBaseURL("http://replay.invalid"), jlcpcb.WithHTTPClient(rec.Client()))
+	ctx := context.Background()
+
+	resp, err := client.KeywordSearch(ctx, jlcpcb.SearchRequest{Keyword: "100nF", PageSize: 10})
+	if err != nil {
+		t.Fatalf("replayed KeywordSearch failed: %v", err)
+	}
+	if resp.TotalCount != 2 {
+		t.Errorf("expected 2 replayed results, got %d", resp.TotalCount)
+	}
+
+	product, err := client.GetProductDetails(ctx, "C8734")
+	if err != nil {
+		t.Fatalf("replayed GetProductDetails failed: %v", err)
+	}
+	if product.ComponentModelEn != "STM32F103C8T6" {
+		t.Errorf("unexpected replayed product %s", product.ComponentModelEn)
+	}
+
+	// Repeated requests reuse the last matching interaction.
+	if _, err := client.GetProductDetails(ctx, "C8734"); err != nil {
+		t.Errorf("repeated replay failed: %v", err)
+	}
+}
+
+// TestRecorderBinaryBody tests that non-UTF-8 bodies round-trip unchanged.
+func TestRecorderBinaryBody(t *testing.T) {
+	pdf := []byte("%PDF-1.4\n\xff\xfe\x00\x80binary")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write(pdf)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "binary.json")
+	rec, err := NewRecorder(path, RecorderConfig{Mode: ModeRecord})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	body := fetch(t, rec.Client(), server.URL+"/datasheet.pdf")
+	if !bytes.Equal(body, pdf) {
+		t.Fatalf("recorded body changed: %q", body)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"body_base64"`) {
+		t.Errorf("expected a base64 body in the cassette, got %s", data)
+	}
+
+	rec, err = NewRecorder(path, RecorderConfig{Mode: ModeReplay})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	if body := fetch(t, rec.Client(), "http://replay.invalid/datasheet.pdf"); !bytes.Equal(body, pdf) {
+		t.Errorf("replayed body changed: %q", body)
+	}
+}
+
+// fetch performs a GET request and returns the response body.
+func fetch(t *testing.T, client *http.Client, url string) []byte {
+	t.Helper()
+
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	return body
+}
+
+// TestRecorderReplayUnmatched tests that unknown requests fail in replay mode.
+func TestRecorderReplayUnmatched(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search.json")
+	recordCassette(t, path)
+
+	rec, err := NewRecorder(path, RecorderConfig{Mode: ModeReplay})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+
+	client := jlcpcb.NewClient(jlcpcb.WithBaseURL("http://replay.invalid"), jlcpcb.WithHTTPClient(rec.Client()))
+	_, err = client.KeywordSearch(context.Background(), jlcpcb.SearchRequest{Keyword: "led"})
+
+	var unmatched ErrUnmatchedRequest
+	if !errors.As(err, &unmatched) {
+		t.Fatalf("expected ErrUnmatchedRequest, got %v", err)
+	}
+	if !strings.Contains(unmatched.Body, `"keyword":"led"`) {
+		t.Errorf("expected normalized body in error, got %q", unmatched.Body)
+	}
+}
+
+// TestRecorderScrubsHeaders tests that volatile headers are not saved.
+func TestRecorderScrubsHeaders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search.json")
+	recordCassette(t, path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, h := range []string{"Date", "Content-Encoding", "Content-Length"} {
+		if strings.Contains(string(data), `"`+h+`"`) {
+			t.Errorf("expected header %s to be scrubbed", h)
+		}
+	}
+	if !strings.Contains(string(data), "Content-Type") {
+		t.Error("expected stable headers to be kept")
+	}
+}
+
+// TestRecorderReplayMissingCassette tests that replay requires a cassette.
+func TestRecorderReplayMissingCassette(t *testing.T) {
+	_, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), RecorderConfig{Mode: ModeReplay})
+	if err == nil {
+		t.Fatal("expected error for missing cassette")
+	}
+}
+
+// TestRecorderAutoMode tests that auto mode records first and replays later.
+func TestRecorderAutoMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auto.json")
+
+	rec, err := NewRecorder(path, RecorderConfig{Mode: ModeAuto})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	if rec.Mode() != ModeRecord {
+		t.Errorf("expected record mode without cassette, got %v", rec.Mode())
+	}
+
+	recordCassette(t, path)
+
+	rec, err = NewRecorder(path, RecorderConfig{Mode: ModeAuto})
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	if rec.Mode() != ModeReplay {
+		t.Errorf("expected replay mode with cassette, got %v", rec.Mode())
+	}
+}
+
+// TestNormalizeBody tests JSON body normalization.
+func TestNormalizeBody(t *testing.T) {
+	a := normalizeBody([]byte(`{"b": 1, "a": {"y": 2.50, "x": null}}`))
+	b := normalizeBody([]byte(`{"a":{"x":null,"y":2.50},"b":1}`))
+
+	if a != b {
+		t.Errorf("expected equal normalized bodies, got %s and %s", a, b)
+	}
+	if got := normalizeBody([]byte("not json")); got != "not json" {
+		t.Errorf("expected raw body, got %q", got)
+	}
+	if got := normalizeBody(nil); got != "" {
+		t.Errorf("expected empty body, got %q", got)
+	}
+}
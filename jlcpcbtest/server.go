@@ -0,0 +1,226 @@
+package jlcpcbtest
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+	"github.com/PatrickWalther/go-jlcpcb-parts/internal/search"
+)
+
+// SearchPath is the path of the search endpoint relative to the base URL.
+const SearchPath = "/selectSmtComponentList/v2"
+
+// Fault describes a failure injected into the next matching requests.
+type Fault struct {
+	Status  int           // HTTP status to return, e.g. 429 or 503 (0 means 200)
+	Code    int           // API code for the JSON wrapper when Status is 0 or 200
+	Message string        // API message for the JSON wrapper
+	Latency time.Duration // Delay before responding
+	Times   int           // Number of requests affected (0 means 1)
+}
+
+// Server is an httptest server that speaks the JLCPCB search API. Point a
+// client at it with jlcpcb.WithBaseURL(server.URL).
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	products []jlcpcb.Product
+	faults   []Fault
+	latency  time.Duration
+	requests []jlcpcb.SearchRequest
+}
+
+// searchBody mirrors the JSON body the client sends to the search endpoint.
+type searchBody struct {
+	Keyword                    string          `json:"keyword"`
+	CurrentPage                int             `json:"currentPage"`
+	PageSize                   int             `json:"pageSize"`
+	PresaleType                string          `json:"presaleType"`
+	ComponentLibraryType       *string         `json:"componentLibraryType"`
+	ComponentAttributeList     []searchAttr    `json:"componentAttributeList"`
+	ComponentBrandList         []string        `json:"componentBrandList"`
+	ComponentSpecificationList []string        `json:"componentSpecificationList"`
+	FirstSortName              *string         `json:"firstSortName"`
+	SecondSortName             *string         `json:"secondSortName"`
+	StockFlag                  bool            `json:"stockFlag"`
+	PreferredComponentFlag     bool            `json:"preferredComponentFlag"`
+	ParamList                  json.RawMessage `json:"paramList"`
+}
+
+// searchAttr mirrors an attribute filter in the search body.
+type searchAttr struct {
+	Name  string `json:"attributeName"`
+	Value string `json:"attributeValue"`
+}
+
+// NewServer starts a server seeded with the given products.
+// The caller should call Close when finished.
+func NewServer(fixtures []jlcpcb.Product) *Server {
+	s := &Server{products: append([]jlcpcb.Product(nil), fixtures...)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(SearchPath, s.handleSearch)
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// InjectFault queues a fault for upcoming search requests. Faults are
+// applied in the order they were injected.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f.Times <= 0 {
+		f.Times = 1
+	}
+	s.faults = append(s.faults, f)
+}
+
+// SetLatency delays every response by d.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = d
+}
+
+// Requests returns the decoded search requests received, in arrival order.
+func (s *Server) Requests() []jlcpcb.SearchRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]jlcpcb.SearchRequest(nil), s.requests...)
+}
+
+// handleSearch serves POST /selectSmtComponentList/v2.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body searchBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, r, http.StatusOK, apiResponse{Code: 400, Message: "invalid request body"})
+		return
+	}
+	req := body.searchRequest()
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	latency := s.latency
+	var fault *Fault
+	if len(s.faults) > 0 {
+		f := s.faults[0]
+		fault = &f
+		s.faults[0].Times--
+		if s.faults[0].Times == 0 {
+			s.faults = s.faults[1:]
+		}
+	}
+	products := s.products
+	s.mu.Unlock()
+
+	if fault != nil {
+		latency += fault.Latency
+	}
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if fault != nil && fault.Status != 0 && fault.Status != http.StatusOK {
+		writeJSON(w, r, fault.Status, apiResponse{Code: fault.Status, Message: http.StatusText(fault.Status)})
+		return
+	}
+	if fault != nil && fault.Code != 0 {
+		writeJSON(w, r, http.StatusOK, apiResponse{Code: fault.Code, Message: fault.Message})
+		return
+	}
+
+	if req.CurrentPage <= 0 {
+		req.CurrentPage = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = 50
+	}
+
+	matched := Filter(products, req)
+	start, end := search.PageBounds(len(matched), req.CurrentPage, req.PageSize)
+
+	resp := apiResponse{Code: 200, Data: &apiData{}}
+	resp.Data.ComponentPageInfo = jlcpcb.SearchResponse{
+		Products:   append([]jlcpcb.Product{}, matched[start:end]...),
+		TotalCount: len(matched),
+		PageSize:   req.PageSize,
+		PageNumber: req.CurrentPage,
+	}
+	writeJSON(w, r, http.StatusOK, resp)
+}
+
+// searchRequest converts the wire body into a SearchRequest.
+func (b *searchBody) searchRequest() jlcpcb.SearchRequest {
+	req := jlcpcb.SearchRequest{
+		Keyword:       b.Keyword,
+		CurrentPage:   b.CurrentPage,
+		PageSize:      b.PageSize,
+		PresaleType:   b.PresaleType,
+		Brands:        b.ComponentBrandList,
+		Packages:      b.ComponentSpecificationList,
+		StockOnly:     b.StockFlag,
+		PreferredOnly: b.PreferredComponentFlag,
+	}
+	if b.ComponentLibraryType != nil {
+		req.ComponentType = *b.ComponentLibraryType
+	}
+	if b.FirstSortName != nil {
+		req.SortBy = *b.FirstSortName
+	}
+	if b.SecondSortName != nil {
+		req.SortBySecondary = *b.SecondSortName
+	}
+	for _, attr := range b.ComponentAttributeList {
+		req.Attributes = append(req.Attributes, jlcpcb.FilterAttribute{Name: attr.Name, Value: attr.Value})
+	}
+	return req
+}
+
+// apiResponse is the code/message/data wrapper used by the JLCPCB API.
+type apiResponse struct {
+	Code    int      `json:"code"`
+	Message string   `json:"message,omitempty"`
+	Data    *apiData `json:"data"`
+}
+
+// apiData holds the search results of an API response.
+type apiData struct {
+	ComponentPageInfo jlcpcb.SearchResponse `json:"componentPageInfo"`
+}
+
+// writeJSON writes v as JSON, gzip-compressed if the client accepts it.
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+
+	if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+		return
+	}
+
+	w.Header().Set("Content-Encoding", "gzip")
+	w.WriteHeader(status)
+	gz := gzip.NewWriter(w)
+	_ = json.NewEncoder(gz).Encode(v)
+	_ = gz.Close()
+}
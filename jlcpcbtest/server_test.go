@@ -0,0 +1,216 @@
+package jlcpcbtest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+)
+
+// newTestClient creates a client for server with fast retries.
+func newTestClient(server *Server) *jlcpcb.Client {
+	return jlcpcb.NewClient(
+		jlcpcb.WithBaseURL(server.URL),
+		jlcpcb.WithRateLimit(1000),
+		jlcpcb.WithRetryConfig(jlcpcb.RetryConfig{
+			MaxRetries:        2,
+			InitialBackoff:    time.Millisecond,
+			MaxBackoff:        time.Millisecond,
+			BackoffMultiplier: 1,
+		}),
+	)
+}
+
+// TestServerSearch tests a search through the real client.
+func TestServerSearch(t *testing.T) {
+	server := NewServer(Fixtures())
+	defer server.Close()
+
+	resp, err := newTestClient(server).KeywordSearch(context.Background(), jlcpcb.SearchRequest{
+		Keyword:    "100nF",
+		Packages:   []string{"0402"},
+		Brands:     []string{"Samsung Electro-Mechanics"},
+		Attributes: []jlcpcb.FilterAttribute{{Name: "Voltage Rated", Value: "16V"}},
+		StockOnly:  true,
+	})
+	if err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+
+	if resp.TotalCount != 1 || resp.Products[0].ComponentCode != "C1525" {
+		t.Errorf("expected C1525, got %+v", resp.Products)
+	}
+
+	reqs := server.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	if reqs[0].Keyword != "100nF" || reqs[0].Packages[0] != "0402" || !reqs[0].StockOnly {
+		t.Errorf("request not decoded correctly: %+v", reqs[0])
+	}
+}
+
+// TestServerPagination tests that pages are sliced like the real API.
+func TestServerPagination(t *testing.T) {
+	server := NewServer(Fixtures())
+	defer server.Close()
+
+	resp, err := newTestClient(server).KeywordSearch(context.Background(), jlcpcb.SearchRequest{
+		Keyword:     "0603",
+		CurrentPage: 2,
+		PageSize:    3,
+	})
+	if err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+
+	if resp.TotalCount != 4 || len(resp.Products) != 1 || resp.PageNumber != 2 || resp.PageSize != 3 {
+		t.Errorf("unexpected page: total %d, len %d, page %d, size %d",
+			resp.TotalCount, len(resp.Products), resp.PageNumber, resp.PageSize)
+	}
+}
+
+// TestServerGzip tests that responses are gzip-compressed when accepted.
+func TestServerGzip(t *testing.T) {
+	server := NewServer(Fixtures())
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+SearchPath, strings.NewReader(`{"keyword":"led"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip response, got %q", resp.Header.Get("Content-Encoding"))
+	}
+
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("invalid gzip stream: %v", err)
+	}
+	body, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+
+	var wrapper struct {
+		Code int `json:"code"`
+	}
+	if err := json.Unmarshal(body, &wrapper); err != nil || wrapper.Code != 200 {
+		t.Errorf("unexpected body %s (err %v)", body, err)
+	}
+}
+
+// TestServerRejectsGet tests that only POST is accepted.
+func TestServerRejectsGet(t *testing.T) {
+	server := NewServer(Fixtures())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + SearchPath)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", resp.StatusCode)
+	}
+}
+
+// TestServerFaultRetried tests that transient faults are retried by the client.
+func TestServerFaultRetried(t *testing.T) {
+	server := NewServer(Fixtures())
+	defer server.Close()
+
+	server.InjectFault(Fault{Status: http.StatusServiceUnavailable})
+	server.InjectFault(Fault{Status: http.StatusTooManyRequests})
+
+	resp, err := newTestClient(server).KeywordSearch(context.Background(), jlcpcb.SearchRequest{Keyword: "C8734"})
+	if err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+	if len(resp.Products) != 1 {
+		t.Errorf("expected 1 product, got %d", len(resp.Products))
+	}
+	if got := len(server.Requests()); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+// TestServerFaultExhaustsRetries tests persistent faults surface as errors.
+func TestServerFaultExhaustsRetries(t *testing.T) {
+	server := NewServer(Fixtures())
+	defer server.Close()
+
+	server.InjectFault(Fault{Status: http.StatusTooManyRequests, Times: 10})
+
+	_, err := newTestClient(server).KeywordSearch(context.Background(), jlcpcb.SearchRequest{Keyword: "led"})
+	if err == nil || !strings.Contains(err.Error(), "max retries") {
+		t.Fatalf("expected max retries error, got %v", err)
+	}
+}
+
+// TestServerAPICodeFault tests faults reported in the JSON wrapper.
+func TestServerAPICodeFault(t *testing.T) {
+	server := NewServer(Fixtures())
+	defer server.Close()
+
+	server.InjectFault(Fault{Code: 429, Message: "too many requests"})
+
+	_, err := newTestClient(server).KeywordSearch(context.Background(), jlcpcb.SearchRequest{Keyword: "led"})
+	if _, ok := err.(jlcpcb.ErrRateLimited); !ok {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+}
+
+// TestServerLatency tests injected latency against a client timeout.
+func TestServerLatency(t *testing.T) {
+	server := NewServer(Fixtures())
+	defer server.Close()
+
+	server.SetLatency(200 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := newTestClient(server).KeywordSearch(ctx, jlcpcb.SearchRequest{Keyword: "led"})
+	if err == nil {
+		t.Fatal("expected timeout error")
+	}
+}
+
+// TestServerInvalidBody tests that malformed bodies get an API error code.
+func TestServerInvalidBody(t *testing.T) {
+	server := NewServer(Fixtures())
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+SearchPath, "application/json", bytes.NewReader([]byte(`{invalid`)))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var wrapper struct {
+		Code int `json:"code"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&wrapper); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if wrapper.Code != 400 {
+		t.Errorf("expected code 400, got %d", wrapper.Code)
+	}
+}
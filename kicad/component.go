@@ -0,0 +1,102 @@
+// Package kicad reads KiCad schematics and netlists, resolves the LCSC part
+// numbers annotated on their symbols through a jlcpcb.PartsAPI, and writes
+// the BOM and CPL files JLCPCB assembly expects.
+package kicad
+
+import (
+	"sort"
+	"strings"
+)
+
+// LCSCFieldNames lists the symbol field names recognized as holding an LCSC
+// part number, compared case-insensitively. The first non-empty match wins.
+var LCSCFieldNames = []string{"LCSC", "LCSC Part", "LCSC Part #", "LCSC#", "JLCPCB Part", "JLCPCB Part #"}
+
+// Component is a placed schematic symbol.
+type Component struct {
+	Reference string            // Reference designator, e.g. "R1"
+	Value     string            // Value field, e.g. "10k"
+	Footprint string            // Footprint, e.g. "Resistor_SMD:R_0402_1005Metric"
+	LCSC      string            // LCSC part number, e.g. "C25744"
+	DNP       bool              // Marked do-not-populate or excluded from the BOM
+	Fields    map[string]string // All other fields by name
+}
+
+// newComponent builds a component from its fields, extracting the LCSC code.
+func newComponent(ref, value, footprint string, fields map[string]string) Component {
+	return Component{
+		Reference: strings.TrimSpace(ref),
+		Value:     strings.TrimSpace(value),
+		Footprint: strings.TrimSpace(footprint),
+		LCSC:      lcscFromFields(fields),
+		Fields:    fields,
+	}
+}
+
+// lcscFromFields returns the LCSC part number from a symbol's fields.
+func lcscFromFields(fields map[string]string) string {
+	for _, want := range LCSCFieldNames {
+		for name, value := range fields {
+			if strings.EqualFold(strings.TrimSpace(name), want) {
+				if code := normalizeLCSC(value); code != "" {
+					return code
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// normalizeLCSC canonicalizes an LCSC part number such as " c25744 ".
+func normalizeLCSC(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) < 2 || code[0] != 'C' {
+		return ""
+	}
+	for _, r := range code[1:] {
+		if r < '0' || r > '9' {
+			return ""
+		}
+	}
+	return code
+}
+
+// isVirtual reports whether a reference belongs to a symbol that is never
+// placed on the board, such as power flags.
+func isVirtual(ref string) bool {
+	return ref == "" || strings.HasPrefix(ref, "#")
+}
+
+// sortComponents orders components by reference designator, comparing the
+// numeric suffix numerically so that R2 sorts before R10.
+func sortComponents(comps []Component) {
+	sort.SliceStable(comps, func(i, j int) bool {
+		return lessReference(comps[i].Reference, comps[j].Reference)
+	})
+}
+
+// lessReference compares reference designators naturally.
+func lessReference(a, b string) bool {
+	pa, na := splitReference(a)
+	pb, nb := splitReference(b)
+	if pa != pb {
+		return pa < pb
+	}
+	if na != nb {
+		return na < nb
+	}
+	return a < b
+}
+
+// splitReference splits "R10" into "R" and 10.
+func splitReference(ref string) (string, int) {
+	i := len(ref)
+	for i > 0 && ref[i-1] >= '0' && ref[i-1] <= '9' {
+		i--
+	}
+	n := 0
+	for _, r := range ref[i:] {
+		n = n*10 + int(r-'0')
+	}
+	return ref[:i], n
+}
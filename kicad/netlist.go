@@ -0,0 +1,65 @@
+package kicad
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// xmlNetlist is the subset of KiCad's XML netlist read by ReadNetlist. The
+// same format is the intermediate file KiCad's BOM plugins receive.
+type xmlNetlist struct {
+	Components []struct {
+		Ref       string `xml:"ref,attr"`
+		Value     string `xml:"value"`
+		Footprint string `xml:"footprint"`
+		Fields    []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"fields>field"`
+		Properties []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:"value,attr"`
+		} `xml:"property"`
+	} `xml:"components>comp"`
+}
+
+// ReadNetlist reads the components of a KiCad XML netlist or BOM
+// intermediate file. Symbol fields are read from both the <fields> element
+// and KiCad 6+ <property> elements. Power symbols are skipped; components
+// with a "dnp" or "exclude_from_bom" property are marked DNP.
+func ReadNetlist(r io.Reader) ([]Component, error) {
+	var netlist xmlNetlist
+	if err := xml.NewDecoder(r).Decode(&netlist); err != nil {
+		return nil, fmt.Errorf("failed to parse netlist: %w", err)
+	}
+
+	var comps []Component
+	for _, c := range netlist.Components {
+		if isVirtual(c.Ref) {
+			continue
+		}
+
+		fields := make(map[string]string)
+		dnp := false
+		for _, p := range c.Properties {
+			switch strings.ToLower(p.Name) {
+			case "dnp", "exclude_from_bom", "exclude_from_board":
+				dnp = true
+			default:
+				fields[p.Name] = p.Value
+			}
+		}
+		for _, f := range c.Fields {
+			fields[f.Name] = f.Value
+		}
+
+		comp := newComponent(c.Ref, c.Value, c.Footprint, fields)
+		comp.DNP = dnp
+		comps = append(comps, comp)
+	}
+
+	sortComponents(comps)
+	return comps, nil
+}
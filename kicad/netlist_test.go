@@ -0,0 +1,67 @@
+package kicad
+
+import (
+	"strings"
+	"testing"
+)
+
+const testNetlist = `<?xml version="1.0" encoding="utf-8"?>
+<export version="E">
+  <components>
+    <comp ref="R10">
+      <value>10k</value>
+      <footprint>Resistor_SMD:R_0402_1005Metric</footprint>
+      <fields>
+        <field name="LCSC Part #">c25744</field>
+      </fields>
+    </comp>
+    <comp ref="R2">
+      <value>10k</value>
+      <footprint>Resistor_SMD:R_0402_1005Metric</footprint>
+      <property name="LCSC" value="C25744"/>
+    </comp>
+    <comp ref="C1">
+      <value>100nF</value>
+      <footprint>Capacitor_SMD:C_0402_1005Metric</footprint>
+      <property name="LCSC" value="C1525"/>
+      <property name="dnp" value=""/>
+    </comp>
+    <comp ref="#PWR01">
+      <value>GND</value>
+    </comp>
+  </components>
+</export>`
+
+// TestReadNetlist tests component, field and DNP extraction.
+func TestReadNetlist(t *testing.T) {
+	comps, err := ReadNetlist(strings.NewReader(testNetlist))
+	if err != nil {
+		t.Fatalf("ReadNetlist failed: %v", err)
+	}
+
+	if len(comps) != 3 {
+		t.Fatalf("expected 3 components, got %d: %+v", len(comps), comps)
+	}
+	for i, ref := range []string{"C1", "R2", "R10"} {
+		if comps[i].Reference != ref {
+			t.Errorf("expected component %d to be %s, got %s", i, ref, comps[i].Reference)
+		}
+	}
+
+	if !comps[0].DNP || comps[0].LCSC != "C1525" {
+		t.Errorf("unexpected C1: %+v", comps[0])
+	}
+	if comps[1].DNP || comps[1].LCSC != "C25744" || comps[1].Value != "10k" {
+		t.Errorf("unexpected R2: %+v", comps[1])
+	}
+	if comps[2].LCSC != "C25744" || comps[2].Footprint != "Resistor_SMD:R_0402_1005Metric" {
+		t.Errorf("unexpected R10: %+v", comps[2])
+	}
+}
+
+// TestReadNetlistInvalid tests that malformed XML returns an error.
+func TestReadNetlistInvalid(t *testing.T) {
+	if _, err := ReadNetlist(strings.NewReader("<export><components>")); err == nil {
+		t.Error("expected error for malformed netlist")
+	}
+}
@@ -0,0 +1,83 @@
+package kicad
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/PatrickWalther/go-jlcpcb-parts/cpl"
+)
+
+// WriteBOM writes a JLCPCB assembly BOM in CSV format with the columns
+// Comment, Designator, Footprint and "LCSC Part #". Components sharing a
+// value, footprint and LCSC code are grouped into one row. DNP components
+// are left out.
+func WriteBOM(w io.Writer, comps []Component) error {
+	type group struct {
+		comment, footprint, lcsc string
+		refs                     []string
+	}
+	var groups []*group
+	index := make(map[[3]string]*group)
+
+	for _, comp := range comps {
+		if comp.DNP || isVirtual(comp.Reference) {
+			continue
+		}
+		key := [3]string{comp.Value, footprintName(comp.Footprint), comp.LCSC}
+		g, ok := index[key]
+		if !ok {
+			g = &group{comment: key[0], footprint: key[1], lcsc: key[2]}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		g.refs = append(g.refs, comp.Reference)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Comment", "Designator", "Footprint", "LCSC Part #"}); err != nil {
+		return fmt.Errorf("failed to write BOM: %w", err)
+	}
+	for _, g := range groups {
+		sort.Slice(g.refs, func(i, j int) bool { return lessReference(g.refs[i], g.refs[j]) })
+		if err := cw.Write([]string{g.comment, strings.Join(g.refs, ","), g.footprint, g.lcsc}); err != nil {
+			return fmt.Errorf("failed to write BOM: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write BOM: %w", err)
+	}
+	return nil
+}
+
+// WriteCPL writes a JLCPCB component placement list for placements read
+// with cpl.ReadKiCad, leaving out those whose reference belongs to a DNP
+// component in comps. Use the cpl package directly to apply rotation
+// corrections first.
+func WriteCPL(w io.Writer, placements []cpl.Placement, comps []Component) error {
+	dnp := make(map[string]bool)
+	for _, comp := range comps {
+		if comp.DNP {
+			dnp[comp.Reference] = true
+		}
+	}
+
+	populated := make([]cpl.Placement, 0, len(placements))
+	for _, p := range placements {
+		if !dnp[p.Designator] {
+			populated = append(populated, p)
+		}
+	}
+	return cpl.WriteCPL(w, populated)
+}
+
+// footprintName strips the library prefix from a KiCad footprint.
+func footprintName(footprint string) string {
+	if i := strings.LastIndex(footprint, ":"); i >= 0 {
+		return footprint[i+1:]
+	}
+	return footprint
+}
@@ -0,0 +1,52 @@
+package kicad
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/PatrickWalther/go-jlcpcb-parts/cpl"
+)
+
+// TestWriteBOM tests grouping, designator ordering and DNP exclusion.
+func TestWriteBOM(t *testing.T) {
+	comps := []Component{
+		{Reference: "R10", Value: "10k", Footprint: "Resistor_SMD:R_0402_1005Metric", LCSC: "C25744"},
+		{Reference: "C1", Value: "100nF", Footprint: "Capacitor_SMD:C_0402_1005Metric", LCSC: "C1525"},
+		{Reference: "R2", Value: "10k", Footprint: "Resistor_SMD:R_0402_1005Metric", LCSC: "C25744"},
+		{Reference: "R3", Value: "10k", Footprint: "Resistor_SMD:R_0402_1005Metric", LCSC: "C25744", DNP: true},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBOM(&buf, comps); err != nil {
+		t.Fatalf("WriteBOM failed: %v", err)
+	}
+
+	want := "Comment,Designator,Footprint,LCSC Part #\n" +
+		"10k,\"R2,R10\",R_0402_1005Metric,C25744\n" +
+		"100nF,C1,C_0402_1005Metric,C1525\n"
+	if buf.String() != want {
+		t.Errorf("unexpected BOM:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+// TestWriteCPL tests CPL output and DNP exclusion.
+func TestWriteCPL(t *testing.T) {
+	positions := []cpl.Placement{
+		{Designator: "R1", X: 120.5, Y: -80.25, Rotation: 90, Layer: cpl.Top},
+		{Designator: "R3", X: 1, Y: 2, Layer: cpl.Top},
+		{Designator: "U1", X: 100, Y: -75, Rotation: 180, Layer: cpl.Bottom},
+	}
+	comps := []Component{{Reference: "R3", DNP: true}}
+
+	var buf bytes.Buffer
+	if err := WriteCPL(&buf, positions, comps); err != nil {
+		t.Fatalf("WriteCPL failed: %v", err)
+	}
+
+	want := "Designator,Mid X,Mid Y,Layer,Rotation\n" +
+		"R1,120.5000mm,-80.2500mm,Top,90\n" +
+		"U1,100.0000mm,-75.0000mm,Bottom,180\n"
+	if buf.String() != want {
+		t.Errorf("unexpected CPL:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
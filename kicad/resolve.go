@@ -0,0 +1,185 @@
+package kicad
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+	"github.com/PatrickWalther/go-jlcpcb-parts/internal/units"
+)
+
+// Mismatch describes a disagreement between a symbol and its LCSC part.
+type Mismatch struct {
+	Field  string // "footprint" or "value"
+	Symbol string // The symbol's footprint or value
+	Part   string // The part's package, attribute or MPN
+}
+
+// Resolution is a component together with the part its LCSC code refers to.
+type Resolution struct {
+	Component  Component
+	Product    *jlcpcb.Product // nil if the component has no LCSC code or lookup failed
+	Err        error           // Lookup error, if any
+	Mismatches []Mismatch      // Disagreements between symbol and part
+}
+
+// Resolve looks up the LCSC code of every populated component through api
+// and checks each symbol's footprint and value against the part. Each code
+// is looked up once. Lookup failures are reported per component; Resolve
+// itself only fails if ctx is done.
+func Resolve(ctx context.Context, api jlcpcb.PartsAPI, comps []Component) ([]Resolution, error) {
+	type lookup struct {
+		product *jlcpcb.Product
+		err     error
+	}
+	lookups := make(map[string]lookup)
+
+	results := make([]Resolution, 0, len(comps))
+	for _, comp := range comps {
+		res := Resolution{Component: comp}
+		if comp.DNP || comp.LCSC == "" {
+			results = append(results, res)
+			continue
+		}
+
+		l, ok := lookups[comp.LCSC]
+		if !ok {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			l.product, l.err = api.GetProductDetails(ctx, comp.LCSC)
+			lookups[comp.LCSC] = l
+		}
+
+		res.Product, res.Err = l.product, l.err
+		if res.Product != nil {
+			res.Mismatches = CheckComponent(comp, res.Product)
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// CheckComponent compares a symbol's footprint and value with a part. The
+// footprint must contain the part's package name, ignoring punctuation and
+// any "(...)" dimensions, so "R_0402_1005Metric" matches "0402". A value
+// with an SI quantity, such as "4k7" or "100nF", must equal the part's
+// resistance, capacitance or inductance; other values must match the MPN.
+func CheckComponent(comp Component, p *jlcpcb.Product) []Mismatch {
+	var mismatches []Mismatch
+
+	if comp.Footprint != "" && p.ComponentSpecificationEn != "" && !footprintMatches(comp.Footprint, p.ComponentSpecificationEn) {
+		mismatches = append(mismatches, Mismatch{Field: "footprint", Symbol: comp.Footprint, Part: p.ComponentSpecificationEn})
+	}
+
+	if comp.Value != "" {
+		if m, ok := checkValue(comp.Value, p); !ok {
+			mismatches = append(mismatches, m)
+		}
+	}
+
+	return mismatches
+}
+
+// footprintMatches reports whether a KiCad footprint names the package.
+func footprintMatches(footprint, pkg string) bool {
+	if i := strings.Index(pkg, "("); i > 0 {
+		pkg = pkg[:i]
+	}
+	fp, want := alnum(footprintName(footprint)), alnum(pkg)
+	return want == "" || strings.Contains(fp, want)
+}
+
+// mpnMatches reports whether a symbol value names a part number. Either may
+// contain the other, and an "x" in the value matches any character, as in
+// KiCad library values such as "STM32F103C8Tx".
+func mpnMatches(value, mpn string) bool {
+	v, m := alnum(value), alnum(mpn)
+	if strings.Contains(v, m) {
+		return true
+	}
+	for i := 0; i+len(v) <= len(m); i++ {
+		if wildcardEqual(v, m[i:i+len(v)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardEqual compares equal-length strings, treating "X" in pattern as
+// matching any character.
+func wildcardEqual(pattern, s string) bool {
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != s[i] && pattern[i] != 'X' {
+			return false
+		}
+	}
+	return true
+}
+
+// valueAttributes lists the part attributes compared with SI values, by unit.
+var valueAttributes = map[string]string{
+	"":  "Resistance",
+	"Ω": "Resistance",
+	"R": "Resistance",
+	"F": "Capacitance",
+	"H": "Inductance",
+}
+
+// checkValue compares a symbol value with a part.
+func checkValue(value string, p *jlcpcb.Product) (Mismatch, bool) {
+	want, unit, ok := ParseValue(value)
+	if !ok {
+		// Not a quantity: expect the value to name the part.
+		if p.ComponentModelEn == "" || mpnMatches(value, p.ComponentModelEn) {
+			return Mismatch{}, true
+		}
+		return Mismatch{Field: "value", Symbol: value, Part: p.ComponentModelEn}, false
+	}
+
+	name, ok := valueAttributes[unit]
+	if !ok {
+		return Mismatch{}, true
+	}
+	for _, attr := range p.Attributes {
+		if !strings.EqualFold(attr.Name, name) {
+			continue
+		}
+		got, _, ok := ParseValue(attr.Value)
+		if !ok || units.Equal(got, want) {
+			return Mismatch{}, true
+		}
+		return Mismatch{Field: "value", Symbol: value, Part: attr.Value}, false
+	}
+
+	// Parts without the attribute cannot be checked.
+	return Mismatch{}, true
+}
+
+// ParseValue parses a component value such as "10k", "4k7", "100nF",
+// "0.1uF", "2R2" or "10kΩ" into a number and a unit ("", "Ω", "R", "F" or
+// "H"). Text after the quantity, such as tolerance or voltage in
+// "100nF 50V", is ignored. ok is false for values that are not quantities.
+func ParseValue(value string) (float64, string, bool) {
+	n, unit, ok := units.Parse(value)
+	if !ok {
+		return 0, "", false
+	}
+	switch unit {
+	case "", "Ω", "R", "F", "H":
+		return n, unit, true
+	}
+	return 0, "", false
+}
+
+// alnum returns s uppercased with everything but letters and digits removed.
+func alnum(s string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToUpper(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,124 @@
+package kicad
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+	"github.com/PatrickWalther/go-jlcpcb-parts/internal/units"
+	"github.com/PatrickWalther/go-jlcpcb-parts/jlcpcbtest"
+)
+
+// TestResolve tests lookups, deduplication and mismatch reporting.
+func TestResolve(t *testing.T) {
+	fake := jlcpcbtest.NewFake(jlcpcbtest.Fixtures()...)
+	comps := []Component{
+		{Reference: "R1", Value: "10k", Footprint: "Resistor_SMD:R_0402_1005Metric", LCSC: "C25744"},
+		{Reference: "R2", Value: "4k7", Footprint: "Resistor_SMD:R_0603_1608Metric", LCSC: "C25744"},
+		{Reference: "C1", Value: "100n", Footprint: "Capacitor_SMD:C_0402_1005Metric", LCSC: "C1525"},
+		{Reference: "U1", Value: "STM32F103C8Tx", Footprint: "Package_QFP:LQFP-48_7x7mm_P0.5mm", LCSC: "C8734"},
+		{Reference: "U2", Value: "RP2040", LCSC: "C999999"},
+		{Reference: "J1", Value: "Conn"},
+		{Reference: "R3", Value: "1k", LCSC: "C25804", DNP: true},
+	}
+
+	results, err := Resolve(context.Background(), fake, comps)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if len(results) != len(comps) {
+		t.Fatalf("expected %d results, got %d", len(comps), len(results))
+	}
+
+	if lookups := fake.Lookups(); len(lookups) != 4 {
+		t.Errorf("expected one lookup per code, got %v", lookups)
+	}
+
+	if r := results[0]; r.Product == nil || r.Err != nil || len(r.Mismatches) != 0 {
+		t.Errorf("expected R1 to resolve cleanly, got %+v", r)
+	}
+
+	r2 := results[1]
+	if len(r2.Mismatches) != 2 {
+		t.Fatalf("expected footprint and value mismatches for R2, got %+v", r2.Mismatches)
+	}
+	if r2.Mismatches[0].Field != "footprint" || r2.Mismatches[0].Part != "0402" {
+		t.Errorf("unexpected footprint mismatch: %+v", r2.Mismatches[0])
+	}
+	if r2.Mismatches[1].Field != "value" || r2.Mismatches[1].Part != "10kΩ" {
+		t.Errorf("unexpected value mismatch: %+v", r2.Mismatches[1])
+	}
+
+	if r := results[2]; len(r.Mismatches) != 0 {
+		t.Errorf("expected C1 to match, got %+v", r.Mismatches)
+	}
+	if r := results[3]; len(r.Mismatches) != 0 {
+		t.Errorf("expected U1 to match, got %+v", r.Mismatches)
+	}
+
+	var notFound jlcpcb.ErrProductNotFound
+	if r := results[4]; r.Product != nil || !errors.As(r.Err, &notFound) {
+		t.Errorf("expected not found error for U2, got %+v", r)
+	}
+	if r := results[5]; r.Product != nil || r.Err != nil {
+		t.Errorf("expected J1 without LCSC code to be skipped, got %+v", r)
+	}
+	if r := results[6]; r.Product != nil || r.Err != nil {
+		t.Errorf("expected DNP R3 to be skipped, got %+v", r)
+	}
+}
+
+// TestResolveCancelled tests that a done context stops resolution.
+func TestResolveCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Resolve(ctx, jlcpcbtest.NewFake(), []Component{{Reference: "R1", LCSC: "C1"}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// TestCheckComponentMPN tests value checks against the part number.
+func TestCheckComponentMPN(t *testing.T) {
+	p := &jlcpcb.Product{ComponentModelEn: "AMS1117-3.3", ComponentSpecificationEn: "SOT-223"}
+
+	if m := CheckComponent(Component{Value: "AMS1117-3.3", Footprint: "Package_TO_SOT_SMD:SOT-223-3_TabPin2"}, p); len(m) != 0 {
+		t.Errorf("expected match, got %+v", m)
+	}
+	m := CheckComponent(Component{Value: "LM1117", Footprint: "Package_TO_SOT_SMD:SOT-23"}, p)
+	if len(m) != 2 || m[1].Part != "AMS1117-3.3" {
+		t.Errorf("expected footprint and value mismatches, got %+v", m)
+	}
+}
+
+// TestParseValue tests SI value parsing.
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		input string
+		value float64
+		unit  string
+		ok    bool
+	}{
+		{"10k", 10e3, "", true},
+		{"4k7", 4.7e3, "", true},
+		{"2R2", 2.2, "", true},
+		{"10R", 10, "R", true},
+		{"10kΩ", 10e3, "Ω", true},
+		{"100nF", 100e-9, "F", true},
+		{"0.1uF 50V", 100e-9, "F", true},
+		{"4.7µH", 4.7e-6, "H", true},
+		{"1M", 1e6, "", true},
+		{"16V", 0, "", false},
+		{"LM358", 0, "", false},
+		{"", 0, "", false},
+	}
+
+	for _, tt := range tests {
+		value, unit, ok := ParseValue(tt.input)
+		if ok != tt.ok || unit != tt.unit || (ok && !units.Equal(value, tt.value)) {
+			t.Errorf("ParseValue(%q) = %v, %q, %v; want %v, %q, %v", tt.input, value, unit, ok, tt.value, tt.unit, tt.ok)
+		}
+	}
+}
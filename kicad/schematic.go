@@ -0,0 +1,264 @@
+package kicad
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ReadSchematic reads the placed symbols of a KiCad 6+ .kicad_sch file.
+// Symbol properties become component fields. Units of one multi-unit symbol
+// are merged, symbols with (in_bom no), (on_board no) or (dnp yes) are
+// marked DNP, and power symbols are skipped. When a symbol lists instance
+// references, for example in a reused hierarchical sheet, one component is
+// returned per reference. KiCad 6 files keep those references in the
+// top-level (symbol_instances ...) section instead, which is used as a
+// fallback.
+func ReadSchematic(r io.Reader) ([]Component, error) {
+	root, err := parseSexpr(bufio.NewReader(r))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schematic: %w", err)
+	}
+	if root.head() != "kicad_sch" {
+		return nil, fmt.Errorf("failed to parse schematic: not a kicad_sch file")
+	}
+
+	sheetRefs := symbolInstances(root)
+	seen := make(map[string]bool)
+	var comps []Component
+	for _, sym := range root.children("symbol") {
+		fields := make(map[string]string)
+		for _, prop := range sym.children("property") {
+			if len(prop.list) >= 3 {
+				fields[prop.list[1].atom] = prop.list[2].atom
+			}
+		}
+
+		dnp := sym.flag("in_bom") == "no" || sym.flag("on_board") == "no" || sym.flag("dnp") == "yes"
+
+		refs := instanceReferences(sym)
+		if len(refs) == 0 {
+			refs = sheetRefs[sym.flag("uuid")]
+		}
+		if len(refs) == 0 {
+			refs = []string{fields["Reference"]}
+		}
+		for _, ref := range refs {
+			if isVirtual(ref) || seen[ref] {
+				continue
+			}
+			seen[ref] = true
+
+			comp := newComponent(ref, fields["Value"], fields["Footprint"], withoutStandardFields(fields))
+			comp.DNP = dnp
+			comps = append(comps, comp)
+		}
+	}
+
+	sortComponents(comps)
+	return comps, nil
+}
+
+// instanceReferences returns the references listed in a symbol's
+// (instances (project ... (path ... (reference "R1")))) block.
+func instanceReferences(sym *sexpr) []string {
+	var refs []string
+	for _, inst := range sym.children("instances") {
+		for _, project := range inst.children("project") {
+			for _, path := range project.children("path") {
+				if ref := path.flag("reference"); ref != "" {
+					refs = append(refs, ref)
+				}
+			}
+		}
+	}
+	return refs
+}
+
+// symbolInstances returns the references in a KiCad 6 top-level
+// (symbol_instances (path "/sheet/symbol" (reference "R1"))) section,
+// keyed by the symbol UUID that ends each path.
+func symbolInstances(root *sexpr) map[string][]string {
+	refs := make(map[string][]string)
+	for _, inst := range root.children("symbol_instances") {
+		for _, path := range inst.children("path") {
+			ref := path.flag("reference")
+			if len(path.list) < 2 || ref == "" {
+				continue
+			}
+			p := path.list[1].atom
+			uuid := p[strings.LastIndex(p, "/")+1:]
+			refs[uuid] = append(refs[uuid], ref)
+		}
+	}
+	return refs
+}
+
+// withoutStandardFields returns fields minus those stored on Component.
+func withoutStandardFields(fields map[string]string) map[string]string {
+	rest := make(map[string]string, len(fields))
+	for name, value := range fields {
+		switch name {
+		case "Reference", "Value", "Footprint":
+		default:
+			rest[name] = value
+		}
+	}
+	return rest
+}
+
+// sexpr is a node of a KiCad S-expression: an atom or a list.
+type sexpr struct {
+	atom string
+	list []*sexpr
+}
+
+// head returns the first atom of a list, which names it.
+func (s *sexpr) head() string {
+	if len(s.list) == 0 {
+		return ""
+	}
+	return s.list[0].atom
+}
+
+// children returns the direct child lists named name.
+func (s *sexpr) children(name string) []*sexpr {
+	var result []*sexpr
+	for _, child := range s.list {
+		if child.head() == name {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// flag returns the value of a (name value) child, or "" if absent.
+func (s *sexpr) flag(name string) string {
+	for _, child := range s.children(name) {
+		if len(child.list) >= 2 {
+			return child.list[1].atom
+		}
+	}
+	return ""
+}
+
+// parseSexpr parses a single S-expression list.
+func parseSexpr(r *bufio.Reader) (*sexpr, error) {
+	tok, err := nextToken(r)
+	if err != nil {
+		return nil, err
+	}
+	if tok != "(" {
+		return nil, fmt.Errorf("expected '(', got %q", tok)
+	}
+	return parseList(r)
+}
+
+// parseList parses list elements up to the closing parenthesis.
+func parseList(r *bufio.Reader) (*sexpr, error) {
+	node := &sexpr{}
+	for {
+		tok, err := nextToken(r)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("unexpected end of input")
+			}
+			return nil, err
+		}
+		switch tok {
+		case "(":
+			child, err := parseList(r)
+			if err != nil {
+				return nil, err
+			}
+			node.list = append(node.list, child)
+		case ")":
+			return node, nil
+		default:
+			node.list = append(node.list, &sexpr{atom: unquote(tok)})
+		}
+	}
+}
+
+// nextToken returns "(", ")", a quoted string including its quotes, or a
+// bare atom.
+func nextToken(r *bufio.Reader) (string, error) {
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			continue
+		case c == '(' || c == ')':
+			return string(c), nil
+		case c == '"':
+			var sb strings.Builder
+			sb.WriteByte('"')
+			for {
+				c, err := r.ReadByte()
+				if err != nil {
+					return "", fmt.Errorf("unterminated string")
+				}
+				sb.WriteByte(c)
+				if c == '\\' {
+					next, err := r.ReadByte()
+					if err != nil {
+						return "", fmt.Errorf("unterminated string")
+					}
+					sb.WriteByte(next)
+					continue
+				}
+				if c == '"' {
+					return sb.String(), nil
+				}
+			}
+		default:
+			var sb strings.Builder
+			sb.WriteByte(c)
+			for {
+				c, err := r.ReadByte()
+				if err != nil {
+					return sb.String(), nil
+				}
+				if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '(' || c == ')' || c == '"' {
+					_ = r.UnreadByte()
+					return sb.String(), nil
+				}
+				sb.WriteByte(c)
+			}
+		}
+	}
+}
+
+// unquote strips quotes and escapes from a string token.
+func unquote(tok string) string {
+	if len(tok) < 2 || tok[0] != '"' {
+		return tok
+	}
+	tok = tok[1 : len(tok)-1]
+	if !strings.Contains(tok, `\`) {
+		return tok
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(tok); i++ {
+		if tok[i] == '\\' && i+1 < len(tok) {
+			i++
+			switch tok[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(tok[i])
+			}
+			continue
+		}
+		sb.WriteByte(tok[i])
+	}
+	return sb.String()
+}
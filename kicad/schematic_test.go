@@ -0,0 +1,152 @@
+package kicad
+
+import (
+	"strings"
+	"testing"
+)
+
+const testSchematic = `(kicad_sch (version 20231120) (generator "eeschema")
+  (lib_symbols
+    (symbol "Device:R" (property "Reference" "R" (at 0 0 0)))
+  )
+  (symbol (lib_id "Device:R") (at 100 50 0) (unit 1)
+    (in_bom yes) (on_board yes) (dnp no)
+    (property "Reference" "R1" (at 0 0 0))
+    (property "Value" "4k7" (at 0 0 0))
+    (property "Footprint" "Resistor_SMD:R_0603_1608Metric" (at 0 0 0))
+    (property "LCSC" "C23162" (at 0 0 0) (effects (hide yes)))
+    (instances
+      (project "board"
+        (path "/a" (reference "R1") (unit 1))
+        (path "/b" (reference "R5") (unit 1))
+      )
+    )
+  )
+  (symbol (lib_id "Device:C") (at 120 50 0) (unit 1)
+    (in_bom no) (on_board yes)
+    (property "Reference" "C3" (at 0 0 0))
+    (property "Value" "1uF \"X7R\"" (at 0 0 0))
+    (property "Footprint" "" (at 0 0 0))
+  )
+  (symbol (lib_id "Amplifier:LM358") (at 140 50 0) (unit 1)
+    (property "Reference" "U1" (at 0 0 0))
+    (property "Value" "LM358" (at 0 0 0))
+    (property "JLCPCB Part #" "C7950" (at 0 0 0))
+  )
+  (symbol (lib_id "Amplifier:LM358") (at 160 50 0) (unit 2)
+    (property "Reference" "U1" (at 0 0 0))
+    (property "Value" "LM358" (at 0 0 0))
+  )
+  (symbol (lib_id "power:GND") (at 0 0 0)
+    (property "Reference" "#PWR02" (at 0 0 0))
+  )
+)`
+
+// TestReadSchematic tests symbol, instance and DNP extraction.
+func TestReadSchematic(t *testing.T) {
+	comps, err := ReadSchematic(strings.NewReader(testSchematic))
+	if err != nil {
+		t.Fatalf("ReadSchematic failed: %v", err)
+	}
+
+	var refs []string
+	for _, c := range comps {
+		refs = append(refs, c.Reference)
+	}
+	if strings.Join(refs, " ") != "C3 R1 R5 U1" {
+		t.Fatalf("unexpected references: %v", refs)
+	}
+
+	c3, r1, r5, u1 := comps[0], comps[1], comps[2], comps[3]
+	if !c3.DNP || c3.Value != `1uF "X7R"` {
+		t.Errorf("unexpected C3: %+v", c3)
+	}
+	if r1.DNP || r1.LCSC != "C23162" || r1.Value != "4k7" || r1.Footprint != "Resistor_SMD:R_0603_1608Metric" {
+		t.Errorf("unexpected R1: %+v", r1)
+	}
+	if r5.LCSC != "C23162" {
+		t.Errorf("expected R5 to share R1's fields, got %+v", r5)
+	}
+	if u1.LCSC != "C7950" {
+		t.Errorf("unexpected U1: %+v", u1)
+	}
+	if _, ok := r1.Fields["Reference"]; ok {
+		t.Error("expected standard fields to be excluded from Fields")
+	}
+}
+
+// testSchematicV6 is a KiCad 6 root sheet whose "amp" sub-sheet is used
+// twice; its references live only in the top-level symbol_instances.
+const testSchematicV6 = `(kicad_sch (version 20211123) (generator eeschema)
+  (uuid 0f1e2d3c-0000-0000-0000-000000000000)
+  (symbol (lib_id "Device:R") (at 100 50 0) (unit 1)
+    (in_bom yes) (on_board yes)
+    (uuid 11111111-0000-0000-0000-000000000000)
+    (property "Reference" "R1" (id 0) (at 0 0 0))
+    (property "Value" "10k" (id 1) (at 0 0 0))
+    (property "LCSC" "C25804" (id 4) (at 0 0 0))
+  )
+  (symbol (lib_id "Device:C") (at 120 50 0) (unit 1)
+    (in_bom yes) (on_board yes)
+    (uuid 22222222-0000-0000-0000-000000000000)
+    (property "Reference" "C?" (id 0) (at 0 0 0))
+    (property "Value" "100nF" (id 1) (at 0 0 0))
+  )
+  (sheet (at 50 50) (size 20 20)
+    (uuid aaaaaaaa-0000-0000-0000-000000000000)
+    (property "Sheet name" "amp1" (id 0) (at 0 0 0))
+    (property "Sheet file" "amp.kicad_sch" (id 1) (at 0 0 0))
+  )
+  (sheet (at 80 50) (size 20 20)
+    (uuid bbbbbbbb-0000-0000-0000-000000000000)
+    (property "Sheet name" "amp2" (id 0) (at 0 0 0))
+    (property "Sheet file" "amp.kicad_sch" (id 1) (at 0 0 0))
+  )
+  (sheet_instances
+    (path "/" (page "1"))
+    (path "/aaaaaaaa-0000-0000-0000-000000000000" (page "2"))
+    (path "/bbbbbbbb-0000-0000-0000-000000000000" (page "3"))
+  )
+  (symbol_instances
+    (path "/11111111-0000-0000-0000-000000000000"
+      (reference "R1") (unit 1) (value "10k") (footprint "")
+    )
+    (path "/aaaaaaaa-0000-0000-0000-000000000000/22222222-0000-0000-0000-000000000000"
+      (reference "C1") (unit 1) (value "100nF") (footprint "")
+    )
+    (path "/bbbbbbbb-0000-0000-0000-000000000000/22222222-0000-0000-0000-000000000000"
+      (reference "C2") (unit 1) (value "100nF") (footprint "")
+    )
+  )
+)`
+
+// TestReadSchematicSymbolInstances tests KiCad 6 top-level symbol_instances.
+func TestReadSchematicSymbolInstances(t *testing.T) {
+	comps, err := ReadSchematic(strings.NewReader(testSchematicV6))
+	if err != nil {
+		t.Fatalf("ReadSchematic failed: %v", err)
+	}
+
+	var refs []string
+	for _, c := range comps {
+		refs = append(refs, c.Reference)
+	}
+	if strings.Join(refs, " ") != "C1 C2 R1" {
+		t.Fatalf("unexpected references: %v", refs)
+	}
+	if comps[0].Value != "100nF" || comps[1].Value != "100nF" {
+		t.Errorf("expected both capacitor instances to share fields, got %+v", comps[:2])
+	}
+	if comps[2].LCSC != "C25804" {
+		t.Errorf("unexpected R1: %+v", comps[2])
+	}
+}
+
+// TestReadSchematicInvalid tests that non-schematic input returns an error.
+func TestReadSchematicInvalid(t *testing.T) {
+	for _, input := range []string{"(kicad_pcb (version 1))", "(kicad_sch (symbol", `(kicad_sch "unterminated`} {
+		if _, err := ReadSchematic(strings.NewReader(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package jlcpcb
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+)
+
+// LogConfig controls which sensitive or noisy fields the client logs.
+type LogConfig struct {
+	RedactKeywords bool // Replace search keywords and part codes with "[redacted]"
+	CacheEvents    bool // Log cache hits and misses
+	Headers        bool // Log outgoing request headers
+}
+
+// WithLogger sets a structured logger for client activity. Records are
+// emitted at debug level for requests and cache lookups, info level for
+// completed searches and warn level for retries and failures.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		if logger == nil {
+			logger = slog.New(discardHandler{})
+		}
+		c.logger = logger
+	}
+}
+
+// WithLogConfig sets which sensitive or noisy fields are logged.
+func WithLogConfig(config LogConfig) ClientOption {
+	return func(c *Client) {
+		c.logConfig = config
+	}
+}
+
+// loggerKey is the context key for a request-scoped logger.
+type loggerKey struct{}
+
+// withRequestLogger returns a context carrying a logger tagged with a new
+// request ID, unless ctx already carries one.
+func (c *Client) withRequestLogger(ctx context.Context) (context.Context, *slog.Logger) {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return ctx, logger
+	}
+	logger := c.logger.With(slog.String("request_id", newRequestID()))
+	return context.WithValue(ctx, loggerKey{}, logger), logger
+}
+
+// keywordAttr returns a log attribute for a search keyword or part code,
+// redacted if configured.
+func (c *Client) keywordAttr(key, value string) slog.Attr {
+	if c.logConfig.RedactKeywords {
+		return slog.String(key, "[redacted]")
+	}
+	return slog.String(key, value)
+}
+
+// logCache logs a cache lookup if cache events are enabled.
+func (c *Client) logCache(ctx context.Context, logger *slog.Logger, key string, hit bool) {
+	if !c.logConfig.CacheEvents {
+		return
+	}
+	msg := "cache miss"
+	if hit {
+		msg = "cache hit"
+	}
+	if c.logConfig.RedactKeywords {
+		logger.DebugContext(ctx, msg)
+		return
+	}
+	logger.DebugContext(ctx, msg, slog.String("key", key))
+}
+
+// logHeaders logs outgoing request headers if enabled.
+func (c *Client) logHeaders(ctx context.Context, logger *slog.Logger, header http.Header) {
+	if !c.logConfig.Headers {
+		return
+	}
+	attrs := make([]any, 0, len(header))
+	for name, values := range header {
+		attrs = append(attrs, slog.Any(name, values))
+	}
+	logger.DebugContext(ctx, "request headers", slog.Group("headers", attrs...))
+}
+
+// newRequestID returns a random identifier for correlating log records.
+func newRequestID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// discardHandler is a slog.Handler that drops all records.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
@@ -0,0 +1,173 @@
+package jlcpcb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (sb *syncBuffer) Write(p []byte) (int, error) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.Write(p)
+}
+
+// records decodes the JSON log records written so far.
+func (sb *syncBuffer) records(t *testing.T) []map[string]interface{} {
+	t.Helper()
+
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(sb.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// newTestLogger creates a debug-level JSON logger writing to a buffer.
+func newTestLogger() (*slog.Logger, *syncBuffer) {
+	buf := &syncBuffer{}
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})), buf
+}
+
+// findRecord returns the first record with the given message.
+func findRecord(records []map[string]interface{}, msg string) map[string]interface{} {
+	for _, r := range records {
+		if r["msg"] == msg {
+			return r
+		}
+	}
+	return nil
+}
+
+// TestLoggerSearchRecords tests the records emitted for a search.
+func TestLoggerSearchRecords(t *testing.T) {
+	server := newSearchServer(t, Product{ComponentCode: "C1"})
+	logger, buf := newTestLogger()
+	client := NewClient(WithBaseURL(server.URL), WithLogger(logger))
+
+	if _, err := client.KeywordSearch(context.Background(), SearchRequest{Keyword: "C1", CurrentPage: 2}); err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+
+	records := buf.records(t)
+	search := findRecord(records, "search completed")
+	if search == nil {
+		t.Fatalf("missing search record in %v", records)
+	}
+	if search["level"] != "INFO" || search["keyword"] != "C1" || search["page"] != float64(2) || search["results"] != float64(1) {
+		t.Errorf("unexpected search record: %v", search)
+	}
+
+	for _, msg := range []string{"request started", "http response", "request completed"} {
+		r := findRecord(records, msg)
+		if r == nil {
+			t.Errorf("missing %q record", msg)
+			continue
+		}
+		if r["request_id"] == nil || r["request_id"] != search["request_id"] {
+			t.Errorf("expected %q to share request_id %v, got %v", msg, search["request_id"], r["request_id"])
+		}
+	}
+
+	if r := findRecord(records, "request headers"); r != nil {
+		t.Error("expected headers not to be logged by default")
+	}
+}
+
+// TestLoggerRetryWarning tests that retries are logged at warn level.
+func TestLoggerRetryWarning(t *testing.T) {
+	server := newFlakyServer(t, 1, Product{ComponentCode: "C1"})
+	logger, buf := newTestLogger()
+	client := NewClient(WithBaseURL(server.URL), WithRetryConfig(fastRetryConfig()), WithLogger(logger))
+
+	if _, err := client.KeywordSearch(context.Background(), SearchRequest{Keyword: "C1"}); err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+
+	r := findRecord(buf.records(t), "retrying request")
+	if r == nil {
+		t.Fatal("missing retry record")
+	}
+	if r["level"] != "WARN" || r["attempt"] != float64(1) || r["error"] == nil {
+		t.Errorf("unexpected retry record: %v", r)
+	}
+}
+
+// TestLoggerConfig tests redaction, cache events and header logging.
+func TestLoggerConfig(t *testing.T) {
+	server := newSearchServer(t, Product{ComponentCode: "C1"})
+	logger, buf := newTestLogger()
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithCache(NewMemoryCache()),
+		WithLogger(logger),
+		WithLogConfig(LogConfig{RedactKeywords: true, CacheEvents: true, Headers: true}),
+	)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.KeywordSearch(context.Background(), SearchRequest{Keyword: "secret-part"}); err != nil {
+			t.Fatalf("KeywordSearch failed: %v", err)
+		}
+	}
+
+	buf.mu.Lock()
+	raw := buf.buf.String()
+	buf.mu.Unlock()
+	if strings.Contains(raw, "secret-part") {
+		t.Errorf("expected keyword to be redacted, got %s", raw)
+	}
+
+	records := buf.records(t)
+	if findRecord(records, "cache miss") == nil || findRecord(records, "cache hit") == nil {
+		t.Error("expected cache events to be logged")
+	}
+
+	headers := findRecord(records, "request headers")
+	if headers == nil {
+		t.Fatal("expected request headers to be logged")
+	}
+	if group, ok := headers["headers"].(map[string]interface{}); !ok || group["User-Agent"] == nil {
+		t.Errorf("unexpected headers record: %v", headers)
+	}
+}
+
+// TestWithLoggerNil tests that a nil logger is replaced by a no-op logger.
+func TestWithLoggerNil(t *testing.T) {
+	client := NewClient(WithLogger(nil))
+
+	if client.logger == nil {
+		t.Fatal("expected non-nil logger")
+	}
+	if client.logger.Enabled(context.Background(), slog.LevelError) {
+		t.Error("expected default logger to discard records")
+	}
+}
+
+// TestNewRequestID tests request ID generation.
+func TestNewRequestID(t *testing.T) {
+	a, b := newRequestID(), newRequestID()
+
+	if len(a) != 16 || a == b {
+		t.Errorf("expected distinct 16-character IDs, got %q and %q", a, b)
+	}
+}
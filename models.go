@@ -1,9 +1,14 @@
 package jlcpcb
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 // Attribute represents a product specification/parameter.
@@ -36,11 +41,77 @@ func (f *FlexFloat64) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("cannot unmarshal %s into FlexFloat64", string(data))
 }
 
+// FlexString handles JSON values that may be either a string or a number.
+type FlexString string
+
+// UnmarshalJSON implements json.Unmarshaler for FlexString.
+func (f *FlexString) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*f = FlexString(str)
+		return nil
+	}
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err == nil {
+		*f = FlexString(num.String())
+		return nil
+	}
+	return fmt.Errorf("cannot unmarshal %s into FlexString", string(data))
+}
+
+// FlexBool handles JSON values that may be a boolean, a 0/1 number or a
+// string such as "true" or "1".
+type FlexBool bool
+
+// UnmarshalJSON implements json.Unmarshaler for FlexBool.
+func (f *FlexBool) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	switch strings.ToLower(text) {
+	case "true", "1", "y", "yes":
+		*f = true
+	case "false", "0", "n", "no", "", "null":
+		*f = false
+	default:
+		return fmt.Errorf("cannot unmarshal %s into FlexBool", string(data))
+	}
+	return nil
+}
+
 // PriceBreak represents a quantity-based price tier.
 type PriceBreak struct {
-	StartNumber  int         `json:"startNumber"`  // Minimum quantity for this tier
-	EndNumber    int         `json:"endNumber"`    // Maximum quantity for this tier (-1 means unlimited)
-	ProductPrice FlexFloat64 `json:"productPrice"` // Price per unit in USD
+	StartNumber  int   `json:"startNumber"`  // Minimum quantity for this tier
+	EndNumber    int   `json:"endNumber"`    // Maximum quantity for this tier (-1 means unlimited)
+	ProductPrice Money `json:"productPrice"` // Price per unit in the product's currency
+}
+
+// priceForQuantity returns the unit price of the tier that applies to the
+// given order quantity. Quantities below the first tier use the first tier.
+func priceForQuantity(breaks []PriceBreak, quantity int) (Money, bool) {
+	if len(breaks) == 0 {
+		return Money{}, false
+	}
+
+	best := -1
+	for i, pb := range breaks {
+		if pb.StartNumber > quantity {
+			continue
+		}
+		if best == -1 || pb.StartNumber > breaks[best].StartNumber {
+			best = i
+		}
+	}
+	if best == -1 {
+		for i, pb := range breaks {
+			if best == -1 || pb.StartNumber < breaks[best].StartNumber {
+				best = i
+			}
+		}
+	}
+
+	return breaks[best].ProductPrice, true
 }
 
 // Product represents a JLCPCB electronic component.
@@ -61,9 +132,168 @@ type Product struct {
 	Describe                 string       `json:"describe"`                 // Description
 	FirstSortName            string       `json:"firstSortName"`            // Primary category
 	SecondSortName           string       `json:"secondSortName"`           // Secondary category
-	IsBuyComponent           string       `json:"isBuyComponent"`           // Can be purchased
+	IsBuyComponent           string       `json:"isBuyComponent"`           // "1" if available through global sourcing; see IsBuyable
 	UrlSuffix                string       `json:"urlSuffix"`                // URL suffix for webpage
 	LcscGoodsUrl             string       `json:"lcscGoodsUrl"`             // LCSC product URL
+	Currency                 string       `json:"currency,omitempty"`       // Currency of the prices, set by the client
+
+	ComponentLibraryType   string         `json:"componentLibraryType,omitempty"`   // "base" or "expand"
+	PreferredComponentFlag FlexBool       `json:"preferredComponentFlag,omitempty"` // Preferred extended part (no extended-part fee)
+	ComponentImageUrl      string         `json:"componentImageUrl,omitempty"`      // Main product image
+	MinImage               string         `json:"minImage,omitempty"`               // Thumbnail image
+	ImageList              []ProductImage `json:"imageList,omitempty"`              // All product images
+	AssemblyProcess        string         `json:"assemblyProcess,omitempty"`        // "SMT" or "THT"
+	LeadTime               FlexString     `json:"leadTime,omitempty"`               // Restock lead time as reported by the site
+	LcscComponentId        FlexString     `json:"lcscComponentId,omitempty"`        // LCSC internal component ID
+	ErpComponentName       string         `json:"erpComponentName,omitempty"`       // JLCPCB internal name
+	FirstSortAccessId      string         `json:"firstSortAccessId,omitempty"`      // Primary category ID
+	SecondSortAccessId     string         `json:"secondSortAccessId,omitempty"`     // Secondary category ID
+	DataManualFileAccessId string         `json:"dataManualFileAccessId,omitempty"` // Datasheet file ID
+	LossNumber             int            `json:"lossNumber,omitempty"`             // Attrition parts added per order
+
+	// Extras holds response fields not mapped above, keyed by JSON name.
+	// They are preserved when the product is marshaled again.
+	Extras map[string]json.RawMessage `json:"-"`
+}
+
+// ProductImage is one entry of a product's image list.
+type ProductImage struct {
+	ProductBigImage string `json:"productBigImage,omitempty"` // Full-size image URL
+	ProductMinImage string `json:"productMinImage,omitempty"` // Thumbnail image URL
+}
+
+// IsBasic reports whether the part is in JLCPCB's basic library, which
+// carries no extended-part loading fee.
+func (p *Product) IsBasic() bool {
+	return strings.EqualFold(p.ComponentLibraryType, "base")
+}
+
+// IsPreferred reports whether the part is a preferred extended part.
+func (p *Product) IsPreferred() bool {
+	return bool(p.PreferredComponentFlag)
+}
+
+// productFields is the set of JSON names mapped to Product fields.
+var productFields = jsonFieldNames(reflect.TypeOf(Product{}))
+
+// UnmarshalJSON implements json.Unmarshaler for Product. Prices are tagged
+// with the product's currency and unmapped fields are kept in Extras.
+func (p *Product) UnmarshalJSON(data []byte) error {
+	type product Product
+	if err := json.Unmarshal(data, (*product)(p)); err != nil {
+		return err
+	}
+	p.setCurrency(p.Currency)
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	p.Extras = nil
+	for name, value := range fields {
+		if productFields[name] {
+			continue
+		}
+		if p.Extras == nil {
+			p.Extras = make(map[string]json.RawMessage)
+		}
+		p.Extras[name] = value
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler for Product, writing Extras back
+// alongside the mapped fields.
+func (p Product) MarshalJSON() ([]byte, error) {
+	type product Product
+	data, err := json.Marshal(product(p))
+	if err != nil || len(p.Extras) == 0 {
+		return data, err
+	}
+
+	names := make([]string, 0, len(p.Extras))
+	for name := range p.Extras {
+		if !productFields[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for _, name := range names {
+		key, _ := json.Marshal(name)
+		buf.WriteByte(',')
+		buf.Write(key)
+		buf.WriteByte(':')
+		if err := json.Compact(&buf, p.Extras[name]); err != nil {
+			return nil, fmt.Errorf("invalid extra field %s: %w", name, err)
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// jsonFieldNames returns the JSON names of a struct type's fields.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// UnitPrice returns the unit price that applies when ordering quantity parts.
+func (p *Product) UnitPrice(quantity int) (Money, bool) {
+	return priceForQuantity(p.ComponentPrices, quantity)
+}
+
+// ExtendedPrice returns the total price of ordering quantity parts.
+func (p *Product) ExtendedPrice(quantity int) (Money, bool) {
+	unit, ok := p.UnitPrice(quantity)
+	if !ok {
+		return Money{}, false
+	}
+	return unit.Mul(quantity), true
+}
+
+// clone returns a deep copy of the product, so callers sharing a coalesced
+// result cannot see each other's changes.
+func (p *Product) clone() Product {
+	c := *p
+	c.ComponentPrices = slices.Clone(p.ComponentPrices)
+	c.BuyComponentPrices = slices.Clone(p.BuyComponentPrices)
+	c.Attributes = slices.Clone(p.Attributes)
+	c.ImageList = slices.Clone(p.ImageList)
+	if p.Extras != nil {
+		c.Extras = make(map[string]json.RawMessage, len(p.Extras))
+		for k, v := range p.Extras {
+			c.Extras[k] = slices.Clone(v)
+		}
+	}
+	return c
+}
+
+// setCurrency sets the product's currency and tags its prices with it.
+func (p *Product) setCurrency(currency string) {
+	p.Currency = currency
+	tagPrices(p.ComponentPrices, currency)
+	tagPrices(p.BuyComponentPrices, currency)
+}
+
+// tagPrices sets the currency of every price break.
+func tagPrices(breaks []PriceBreak, currency string) {
+	for i := range breaks {
+		breaks[i].ProductPrice.Currency = currency
+	}
 }
 
 // GetProductURL returns the JLCPCB product page URL.
@@ -87,12 +317,13 @@ type SearchRequest struct {
 	PageSize    int
 	IsAvailable bool
 	// Advanced filters
-	PresaleType     string            // "stock", "buy", "post", or empty for all
+	PresaleType     string            // A SourcingMode ("stock", "buy", "post"); empty means "stock"
 	ComponentType   string            // "base" or "expand"
 	Attributes      []FilterAttribute // Filter by attributes
 	Brands          []string          // Filter by brand names
+	Packages        []string          // Filter by package/footprint names
 	StockOnly       bool              // Only show in-stock items
-	PreferredOnly   bool              // Only show preferred components
+	PreferredOnly   bool              // Only show preferred extended components
 	SortBy          string            // Primary sort field
 	SortBySecondary string            // Secondary sort field
 }
@@ -162,7 +162,7 @@ func TestPriceBreakValidation(t *testing.T) {
 	pb := PriceBreak{
 		StartNumber:  1,
 		EndNumber:    49,
-		ProductPrice: FlexFloat64(9.99),
+		ProductPrice: MoneyFromFloat(9.99, "USD"),
 	}
 
 	if pb.StartNumber != 1 {
@@ -173,8 +173,8 @@ func TestPriceBreakValidation(t *testing.T) {
 		t.Errorf("expected end number 49, got %d", pb.EndNumber)
 	}
 
-	if float64(pb.ProductPrice) != 9.99 {
-		t.Errorf("expected price 9.99, got %f", pb.ProductPrice)
+	if pb.ProductPrice.Decimal() != "9.99" {
+		t.Errorf("expected price 9.99, got %v", pb.ProductPrice)
 	}
 }
 
@@ -190,7 +190,7 @@ func TestProductStructure(t *testing.T) {
 		DataManualUrl:            "https://example.com/datasheet.pdf",
 		StockCount:               549,
 		MinPurchaseNum:           1,
-		ComponentPrices:          []PriceBreak{{StartNumber: 1, EndNumber: 49, ProductPrice: 4.09}},
+		ComponentPrices:          []PriceBreak{{StartNumber: 1, EndNumber: 49, ProductPrice: MoneyFromFloat(4.09, "USD")}},
 		Attributes:               []Attribute{{Name: "Output Current(Max)", Value: "600mA"}},
 		FirstSortName:            "DC-DC Power Modules",
 		SecondSortName:           "Power Modules",
@@ -251,3 +251,62 @@ func TestSearchRequestStructure(t *testing.T) {
 		t.Error("expected IsAvailable to be true")
 	}
 }
+
+// TestProductExtrasRoundTrip tests that unmapped fields survive decoding and encoding.
+func TestProductExtrasRoundTrip(t *testing.T) {
+	data := `{"componentCode":"C1","componentLibraryType":"expand","preferredComponentFlag":1,` +
+		`"leadTime":14,"lcscComponentId":"123","imageList":[{"productBigImage":"big.jpg"}],` +
+		`"rohsFlag":true,"newSiteField":{"a":[1,2]}}`
+
+	var p Product
+	if err := json.Unmarshal([]byte(data), &p); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	if p.ComponentLibraryType != "expand" || !p.IsPreferred() || p.IsBasic() {
+		t.Errorf("unexpected library fields: %+v", p)
+	}
+	if p.LeadTime != "14" || p.LcscComponentId != "123" {
+		t.Errorf("expected flexible strings, got %q and %q", p.LeadTime, p.LcscComponentId)
+	}
+	if len(p.ImageList) != 1 || p.ImageList[0].ProductBigImage != "big.jpg" {
+		t.Errorf("unexpected image list: %+v", p.ImageList)
+	}
+	if len(p.Extras) != 2 || string(p.Extras["newSiteField"]) != `{"a":[1,2]}` {
+		t.Errorf("unexpected extras: %v", p.Extras)
+	}
+
+	out, err := json.Marshal(&p)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var again Product
+	if err := json.Unmarshal(out, &again); err != nil {
+		t.Fatalf("unmarshal of marshaled product failed: %v", err)
+	}
+	if string(again.Extras["rohsFlag"]) != "true" || string(again.Extras["newSiteField"]) != `{"a":[1,2]}` {
+		t.Errorf("expected extras to round-trip, got %s", out)
+	}
+}
+
+// TestFlexBoolUnmarshal tests the accepted boolean encodings.
+func TestFlexBoolUnmarshal(t *testing.T) {
+	for input, want := range map[string]bool{
+		`true`: true, `1`: true, `"1"`: true, `"true"`: true,
+		`false`: false, `0`: false, `"0"`: false, `null`: false,
+	} {
+		var b FlexBool
+		if err := json.Unmarshal([]byte(input), &b); err != nil {
+			t.Errorf("unmarshal %s failed: %v", input, err)
+			continue
+		}
+		if bool(b) != want {
+			t.Errorf("unmarshal %s: expected %v, got %v", input, want, b)
+		}
+	}
+
+	var b FlexBool
+	if err := json.Unmarshal([]byte(`"maybe"`), &b); err == nil {
+		t.Error("expected error for invalid boolean")
+	}
+}
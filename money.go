@@ -0,0 +1,194 @@
+package jlcpcb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// moneyScale is the number of units per whole currency unit. Money keeps
+// eight decimal places, enough for JLCPCB's sub-cent passive prices.
+const moneyScale = 100000000
+
+// Money is a fixed-point monetary amount with eight decimal places.
+// Arithmetic on Money is exact, so extending unit prices by thousands of
+// parts and summing a BOM accumulates no rounding error.
+//
+// Money marshals to JSON as a bare number and unmarshals from either a number
+// or a numeric string. The currency is not part of the JSON form; it is
+// restored from the enclosing Product or Snapshot.
+type Money struct {
+	units    int64  // amount in 1e-8 currency units
+	Currency string // ISO 4217 code, empty if unknown
+}
+
+// ParseMoney parses a decimal amount such as "0.0123". Digits beyond the
+// eighth decimal place are rounded half away from zero.
+func ParseMoney(amount, currency string) (Money, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok {
+		return Money{}, fmt.Errorf("cannot parse %q as money", amount)
+	}
+	units, err := ratToUnits(r)
+	if err != nil {
+		return Money{}, fmt.Errorf("cannot parse %q as money: %w", amount, err)
+	}
+	return Money{units: units, Currency: currency}, nil
+}
+
+// MoneyFromFloat converts a float amount, rounding to eight decimal places.
+func MoneyFromFloat(amount float64, currency string) Money {
+	return Money{units: int64(math.Round(amount * moneyScale)), Currency: currency}
+}
+
+// Float64 returns the amount as a float, for display or statistics.
+func (m Money) Float64() float64 {
+	return float64(m.units) / moneyScale
+}
+
+// Decimal returns the amount as a decimal string without trailing zeros.
+func (m Money) Decimal() string {
+	units := m.units
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+	whole, frac := units/moneyScale, units%moneyScale
+	if frac == 0 {
+		return sign + strconv.FormatInt(whole, 10)
+	}
+	fracStr := strings.TrimRight(fmt.Sprintf("%08d", frac), "0")
+	return sign + strconv.FormatInt(whole, 10) + "." + fracStr
+}
+
+// String returns the amount followed by the currency code, if known.
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + m.Currency
+}
+
+// IsZero reports whether the amount is zero.
+func (m Money) IsZero() bool {
+	return m.units == 0
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of the amount.
+func (m Money) Sign() int {
+	switch {
+	case m.units < 0:
+		return -1
+	case m.units > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Cmp compares the amounts of m and o, ignoring currency.
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m.units < o.units:
+		return -1
+	case m.units > o.units:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Mul returns the amount multiplied by a quantity.
+func (m Money) Mul(quantity int) Money {
+	return Money{units: m.units * int64(quantity), Currency: m.Currency}
+}
+
+// MulRate returns the amount multiplied by a rate, such as an exchange
+// rate, rounded to eight decimal places.
+func (m Money) MulRate(rate float64) Money {
+	factor := new(big.Rat).SetFloat64(rate)
+	if factor == nil {
+		return Money{units: int64(math.Round(float64(m.units) * rate)), Currency: m.Currency}
+	}
+	units, err := roundRat(factor.Mul(factor, new(big.Rat).SetInt64(m.units)))
+	if err != nil {
+		units = int64(math.Round(float64(m.units) * rate))
+	}
+	return Money{units: units, Currency: m.Currency}
+}
+
+// Add returns the sum of m and o. An empty currency matches any other; two
+// different currencies are an error.
+func (m Money) Add(o Money) (Money, error) {
+	currency := m.Currency
+	if currency == "" {
+		currency = o.Currency
+	} else if o.Currency != "" && o.Currency != currency {
+		return Money{}, fmt.Errorf("cannot add %s to %s", o.Currency, currency)
+	}
+	return Money{units: m.units + o.units, Currency: currency}, nil
+}
+
+// MarshalJSON implements json.Marshaler for Money.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal()), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for Money. The currency is left
+// unchanged.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var text string
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return fmt.Errorf("cannot unmarshal %s into Money: %w", string(data), err)
+		}
+	} else {
+		var num json.Number
+		if err := json.Unmarshal(data, &num); err != nil {
+			return fmt.Errorf("cannot unmarshal %s into Money", string(data))
+		}
+		text = num.String()
+	}
+
+	parsed, err := ParseMoney(text, m.Currency)
+	if err != nil {
+		return err
+	}
+	m.units = parsed.units
+	return nil
+}
+
+// ratToUnits converts an amount in whole currency units to Money units.
+func ratToUnits(r *big.Rat) (int64, error) {
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt64(moneyScale))
+	return roundRat(scaled)
+}
+
+// roundRat rounds r half away from zero to an int64.
+func roundRat(r *big.Rat) (int64, error) {
+	num, den := new(big.Int).Set(r.Num()), r.Denom()
+	neg := num.Sign() < 0
+	num.Abs(num)
+
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Lsh(rem, 1).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if neg {
+		q.Neg(q)
+	}
+	if !q.IsInt64() {
+		return 0, fmt.Errorf("amount out of range")
+	}
+	return q.Int64(), nil
+}
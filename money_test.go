@@ -0,0 +1,137 @@
+package jlcpcb
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestParseMoney tests exact decimal parsing and rounding.
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"0.0123", "0.0123"},
+		{"4.09", "4.09"},
+		{"12", "12"},
+		{"-0.5", "-0.5"},
+		{"1e-3", "0.001"},
+		{"0.000000015", "0.00000002"},
+		{"-0.000000015", "-0.00000002"},
+		{"0.000000014", "0.00000001"},
+	}
+
+	for _, tt := range tests {
+		m, err := ParseMoney(tt.input, "USD")
+		if err != nil {
+			t.Errorf("ParseMoney(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if m.Decimal() != tt.want {
+			t.Errorf("ParseMoney(%q) = %s, want %s", tt.input, m.Decimal(), tt.want)
+		}
+	}
+
+	for _, input := range []string{"", "abc", "1.2.3", "1e30"} {
+		if _, err := ParseMoney(input, ""); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+// TestMoneyString tests formatting with and without a currency.
+func TestMoneyString(t *testing.T) {
+	if got := MoneyFromFloat(0.1, "EUR").String(); got != "0.1 EUR" {
+		t.Errorf("expected 0.1 EUR, got %s", got)
+	}
+	if got := MoneyFromFloat(3, "").String(); got != "3" {
+		t.Errorf("expected 3, got %s", got)
+	}
+}
+
+// TestMoneyArithmetic tests that extending and summing prices is exact.
+func TestMoneyArithmetic(t *testing.T) {
+	unit, _ := ParseMoney("0.0021", "USD")
+
+	total := Money{Currency: "USD"}
+	for i := 0; i < 1000; i++ {
+		var err error
+		total, err = total.Add(unit.Mul(3))
+		if err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+	if total.Decimal() != "6.3" {
+		t.Errorf("expected exact total 6.3, got %s", total.Decimal())
+	}
+
+	if _, err := unit.Add(MoneyFromFloat(1, "EUR")); err == nil {
+		t.Error("expected error adding different currencies")
+	}
+	if sum, err := unit.Add(MoneyFromFloat(1, "")); err != nil || sum.Currency != "USD" {
+		t.Errorf("expected untagged amount to adopt USD, got %v, %v", sum, err)
+	}
+
+	if got := unit.MulRate(0.5); got.Decimal() != "0.00105" {
+		t.Errorf("expected 0.00105, got %s", got.Decimal())
+	}
+	if unit.Cmp(unit.Mul(2)) >= 0 || unit.Sign() != 1 || !(Money{}).IsZero() {
+		t.Error("unexpected comparison results")
+	}
+}
+
+// TestMoneyJSON tests unmarshaling numbers and strings and marshaling numbers.
+func TestMoneyJSON(t *testing.T) {
+	var pb PriceBreak
+	if err := json.Unmarshal([]byte(`{"startNumber":1,"productPrice":0.0123}`), &pb); err != nil {
+		t.Fatalf("unmarshal number failed: %v", err)
+	}
+	if pb.ProductPrice.Decimal() != "0.0123" {
+		t.Errorf("expected 0.0123, got %s", pb.ProductPrice.Decimal())
+	}
+
+	if err := json.Unmarshal([]byte(`{"productPrice":"1.50"}`), &pb); err != nil {
+		t.Fatalf("unmarshal string failed: %v", err)
+	}
+	if pb.ProductPrice.Decimal() != "1.5" {
+		t.Errorf("expected 1.5, got %s", pb.ProductPrice.Decimal())
+	}
+
+	data, err := json.Marshal(pb)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if string(data) != `{"startNumber":1,"endNumber":0,"productPrice":1.5}` {
+		t.Errorf("unexpected JSON %s", data)
+	}
+
+	for _, input := range []string{`{"productPrice":"abc"}`, `{"productPrice":true}`} {
+		if err := json.Unmarshal([]byte(input), &pb); err == nil {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
+
+// TestProductUnmarshalTagsCurrency tests that prices inherit the product currency.
+func TestProductUnmarshalTagsCurrency(t *testing.T) {
+	var p Product
+	data := `{"componentCode":"C1","currency":"EUR","componentPrices":[{"startNumber":1,"productPrice":0.5},{"startNumber":100,"productPrice":"0.25"}]}`
+	if err := json.Unmarshal([]byte(data), &p); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+
+	for _, pb := range p.ComponentPrices {
+		if pb.ProductPrice.Currency != "EUR" {
+			t.Errorf("expected EUR price, got %v", pb.ProductPrice)
+		}
+	}
+
+	unit, ok := p.UnitPrice(150)
+	if !ok || unit.String() != "0.25 EUR" {
+		t.Errorf("expected unit price 0.25 EUR, got %v", unit)
+	}
+	total, ok := p.ExtendedPrice(150)
+	if !ok || total.String() != "37.5 EUR" {
+		t.Errorf("expected extended price 37.5 EUR, got %v", total)
+	}
+}
@@ -0,0 +1,282 @@
+package jlcpcb
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// Confidence rates how certain a resolved part is.
+type Confidence int
+
+const (
+	// ConfidenceNone means no candidate matched.
+	ConfidenceNone Confidence = iota
+	// ConfidenceLow means the best candidate only partly matches, for
+	// example a longer ordering code or a different manufacturer.
+	ConfidenceLow
+	// ConfidenceMedium means the MPN matches but the manufacturer could not
+	// confirm it or another candidate matches equally well.
+	ConfidenceMedium
+	// ConfidenceHigh means the MPN and manufacturer match a single part.
+	ConfidenceHigh
+)
+
+// String returns the confidence level name.
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceLow:
+		return "low"
+	case ConfidenceMedium:
+		return "medium"
+	case ConfidenceHigh:
+		return "high"
+	default:
+		return "none"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler, so confidences appear by
+// name in JSON.
+func (c Confidence) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// Match quality of a candidate's MPN, from best to worst.
+const (
+	mpnExact      = 100 // Same MPN ignoring case and surrounding space
+	mpnNormalized = 80  // Same MPN ignoring punctuation and packaging suffixes
+	mpnPrefix     = 40  // One MPN extends the other, e.g. an ordering code
+	brandMatch    = 20  // Manufacturer matches
+	brandMismatch = -30 // Manufacturer given and different
+)
+
+// MPNMatch is a candidate part for a manufacturer part number.
+type MPNMatch struct {
+	Product    *Product
+	Score      int        // Higher is better; only meaningful for ranking
+	Confidence Confidence // Confidence if this candidate were chosen
+	MPNMatch   string     // "exact", "normalized" or "prefix"
+	BrandMatch *bool      // nil if no manufacturer was given
+}
+
+// MPNResolution is the result of ResolveMPN.
+type MPNResolution struct {
+	MPN          string
+	Manufacturer string
+	Best         *MPNMatch  // nil if nothing matched
+	Alternatives []MPNMatch // Other matching candidates, best first
+}
+
+// Code returns the LCSC code of the best match, or "" if none.
+func (r *MPNResolution) Code() string {
+	if r.Best == nil {
+		return ""
+	}
+	return r.Best.Product.ComponentCode
+}
+
+// Confidence returns the confidence of the best match.
+func (r *MPNResolution) Confidence() Confidence {
+	if r.Best == nil {
+		return ConfidenceNone
+	}
+	return r.Best.Confidence
+}
+
+// mpnSearchSize is the number of results searched per MPN.
+const mpnSearchSize = 30
+
+// ResolveMPN finds the LCSC part for a manufacturer part number. It
+// searches by MPN, and again without packaging suffixes such as "-TR" or
+// "/TR" if the first search finds no equivalent part. Candidates are
+// ranked by MPN match (exact, then equal ignoring punctuation and packaging
+// suffixes, then one extending the other) and by manufacturer, if given;
+// ties go to the part with more stock, then basic parts. Candidates that
+// match equally well are returned as alternatives and lower the
+// confidence. manufacturer may be empty.
+func (c *Client) ResolveMPN(ctx context.Context, mpn, manufacturer string) (*MPNResolution, error) {
+	mpn, manufacturer = strings.TrimSpace(mpn), strings.TrimSpace(manufacturer)
+	if mpn == "" {
+		return nil, ErrInvalidInput{Message: "mpn is required"}
+	}
+
+	seen := make(map[string]bool)
+	var candidates []MPNMatch
+	search := func(keyword string) error {
+		resp, err := c.KeywordSearch(ctx, SearchRequest{Keyword: keyword, PageSize: mpnSearchSize})
+		if err != nil {
+			return err
+		}
+		for i := range resp.Products {
+			p := &resp.Products[i]
+			if seen[p.ComponentCode] {
+				continue
+			}
+			seen[p.ComponentCode] = true
+			if m, ok := scoreMPN(p, mpn, manufacturer); ok {
+				candidates = append(candidates, m)
+			}
+		}
+		return nil
+	}
+
+	if err := search(mpn); err != nil {
+		return nil, err
+	}
+	if base := stripPackagingSuffix(strings.ToUpper(mpn)); !hasEquivalent(candidates) && base != strings.ToUpper(mpn) {
+		if err := search(base); err != nil {
+			return nil, err
+		}
+	}
+
+	return rankMPNMatches(mpn, manufacturer, candidates), nil
+}
+
+// hasEquivalent reports whether any candidate matches the MPN exactly or
+// after normalization.
+func hasEquivalent(candidates []MPNMatch) bool {
+	for _, m := range candidates {
+		if m.MPNMatch != "prefix" {
+			return true
+		}
+	}
+	return false
+}
+
+// scoreMPN scores a candidate, returning false if its MPN does not match.
+func scoreMPN(p *Product, mpn, manufacturer string) (MPNMatch, bool) {
+	m := MPNMatch{Product: p}
+	want, got := NormalizeMPN(mpn), NormalizeMPN(p.ComponentModelEn)
+	switch {
+	case got == "" || want == "":
+		return m, false
+	case strings.EqualFold(strings.TrimSpace(p.ComponentModelEn), mpn):
+		m.Score, m.MPNMatch = mpnExact, "exact"
+	case got == want:
+		m.Score, m.MPNMatch = mpnNormalized, "normalized"
+	case strings.HasPrefix(got, want) || strings.HasPrefix(want, got):
+		m.Score, m.MPNMatch = mpnPrefix, "prefix"
+	default:
+		return m, false
+	}
+
+	if manufacturer != "" {
+		ok := brandMatches(p.ComponentBrandEn, manufacturer)
+		m.BrandMatch = &ok
+		if ok {
+			m.Score += brandMatch
+		} else {
+			m.Score += brandMismatch
+		}
+	}
+	return m, true
+}
+
+// rankMPNMatches sorts candidates and assigns confidence levels.
+func rankMPNMatches(mpn, manufacturer string, candidates []MPNMatch) *MPNResolution {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Product.StockCount != b.Product.StockCount {
+			return a.Product.StockCount > b.Product.StockCount
+		}
+		return a.Product.IsBasic() && !b.Product.IsBasic()
+	})
+
+	for i := range candidates {
+		m := &candidates[i]
+		tied := false
+		for j := range candidates {
+			if j != i && candidates[j].Score == m.Score {
+				tied = true
+				break
+			}
+		}
+		switch {
+		case m.MPNMatch == "prefix" || (m.BrandMatch != nil && !*m.BrandMatch):
+			m.Confidence = ConfidenceLow
+		case m.BrandMatch != nil && *m.BrandMatch && !tied:
+			m.Confidence = ConfidenceHigh
+		default:
+			m.Confidence = ConfidenceMedium
+		}
+	}
+
+	res := &MPNResolution{MPN: mpn, Manufacturer: manufacturer}
+	if len(candidates) > 0 {
+		res.Best = &candidates[0]
+		res.Alternatives = candidates[1:]
+	}
+	return res
+}
+
+// packagingSuffixes are ordering suffixes that select reel, tape or
+// lead-free packaging rather than a different part. Longer suffixes come
+// first.
+var packagingSuffixes = []string{
+	"-REEL7", "-REEL13", "-REEL", "/REEL", "#TRPBF", "#PBF", "-T/R", "/T/R",
+	"-TR", "/TR", "_TR", "#TR", "-PBF", "/PBF", "-CT", "/CT",
+}
+
+// stripPackagingSuffix removes packaging suffixes from an upper-case MPN.
+func stripPackagingSuffix(mpn string) string {
+	for {
+		stripped := false
+		for _, suffix := range packagingSuffixes {
+			if len(mpn) > len(suffix) && strings.HasSuffix(mpn, suffix) {
+				mpn = strings.TrimSuffix(mpn, suffix)
+				stripped = true
+				break
+			}
+		}
+		if !stripped {
+			return mpn
+		}
+	}
+}
+
+// NormalizeMPN returns an MPN in a form for comparison: upper case, with
+// packaging suffixes such as "-TR", "/TR" or "#PBF" removed, and without
+// punctuation or spaces. "LM358DR-TR" and "lm358dr" both normalize to
+// "LM358DR".
+func NormalizeMPN(mpn string) string {
+	return alphanumeric(stripPackagingSuffix(strings.ToUpper(strings.TrimSpace(mpn))))
+}
+
+// brandMatches reports whether a product's manufacturer names the given
+// manufacturer. JLCPCB often writes brands as "NAME(Full Name)", so both
+// parts are compared, and either may contain the other. Names shorter
+// than four characters, like "ST" or "TI", must start the brand.
+func brandMatches(productBrand, manufacturer string) bool {
+	want := alphanumeric(strings.ToUpper(manufacturer))
+	if len(want) < 2 {
+		return false
+	}
+	names := strings.FieldsFunc(productBrand, func(r rune) bool { return r == '(' || r == ')' })
+	names = append(names, productBrand)
+	for _, name := range names {
+		got := alphanumeric(strings.ToUpper(name))
+		switch {
+		case len(got) < 2:
+		case strings.HasPrefix(got, want),
+			len(want) >= 4 && strings.Contains(got, want),
+			len(got) >= 4 && strings.Contains(want, got):
+			return true
+		}
+	}
+	return false
+}
+
+// alphanumeric removes everything but ASCII letters and digits.
+func alphanumeric(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		if (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
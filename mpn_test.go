@@ -0,0 +1,175 @@
+package jlcpcb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newMPNServer starts a search server that returns the products whose MPN
+// contains the keyword, and records the keywords searched.
+func newMPNServer(t *testing.T, products ...Product) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var keywords []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body searchRequestBody
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		keywords = append(keywords, body.Keyword)
+		mu.Unlock()
+
+		var matched []Product
+		for _, p := range products {
+			if strings.Contains(strings.ToUpper(p.ComponentModelEn), strings.ToUpper(body.Keyword)) {
+				matched = append(matched, p)
+			}
+		}
+		var wrapper productSearchWrapper
+		wrapper.Code = 200
+		wrapper.Data.ComponentPageInfo = SearchResponse{Products: matched, TotalCount: len(matched), PageSize: body.PageSize, PageNumber: 1}
+		_ = json.NewEncoder(w).Encode(wrapper)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), keywords...)
+	}
+}
+
+// TestNormalizeMPN tests punctuation and packaging suffix removal.
+func TestNormalizeMPN(t *testing.T) {
+	tests := map[string]string{
+		"LM358DR-TR":       "LM358DR",
+		"lm358dr/tr":       "LM358DR",
+		" AMS1117-3.3 ":    "AMS111733",
+		"LT1763CS8#TRPBF":  "LT1763CS8",
+		"ADP151AUJZ-REEL7": "ADP151AUJZ",
+		"TR":               "TR",
+		"BSS138-T/R-PBF":   "BSS138",
+	}
+	for input, want := range tests {
+		if got := NormalizeMPN(input); got != want {
+			t.Errorf("NormalizeMPN(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+// TestBrandMatches tests manufacturer comparison.
+func TestBrandMatches(t *testing.T) {
+	tests := []struct {
+		brand, manufacturer string
+		want                bool
+	}{
+		{"UNI-ROYAL(Uniroyal Elec)", "Uniroyal", true},
+		{"UNI-ROYAL(Uniroyal Elec)", "UNI-ROYAL", true},
+		{"STMicroelectronics", "ST", true},
+		{"Texas Instruments", "Texas Instruments Inc.", true},
+		{"WINSTAR Display", "ST", false},
+		{"onsemi", "Texas Instruments", false},
+		{"onsemi", "", false},
+	}
+	for _, tt := range tests {
+		if got := brandMatches(tt.brand, tt.manufacturer); got != tt.want {
+			t.Errorf("brandMatches(%q, %q) = %v, want %v", tt.brand, tt.manufacturer, got, tt.want)
+		}
+	}
+}
+
+// TestResolveMPN tests ranking, confidence and alternatives.
+func TestResolveMPN(t *testing.T) {
+	server, _ := newMPNServer(t,
+		Product{ComponentCode: "C7950", ComponentModelEn: "LM358DR", ComponentBrandEn: "TI(Texas Instruments)", StockCount: 500},
+		Product{ComponentCode: "C7951", ComponentModelEn: "LM358DR", ComponentBrandEn: "UMW(Youtai Semiconductor)", StockCount: 90000},
+		Product{ComponentCode: "C7952", ComponentModelEn: "LM358DR2G", ComponentBrandEn: "onsemi", StockCount: 10},
+		Product{ComponentCode: "C1", ComponentModelEn: "NE555", ComponentBrandEn: "TI(Texas Instruments)"},
+	)
+	client := NewClient(WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	res, err := client.ResolveMPN(ctx, "LM358DR", "Texas Instruments")
+	if err != nil {
+		t.Fatalf("ResolveMPN failed: %v", err)
+	}
+	if res.Code() != "C7950" || res.Confidence() != ConfidenceHigh || res.Best.MPNMatch != "exact" {
+		t.Errorf("expected high-confidence TI part, got %s %v %+v", res.Code(), res.Confidence(), res.Best)
+	}
+	if len(res.Alternatives) != 2 || res.Alternatives[0].Product.ComponentCode != "C7951" || res.Alternatives[1].Confidence != ConfidenceLow {
+		t.Errorf("unexpected alternatives %+v", res.Alternatives)
+	}
+
+	// Without a manufacturer, two parts match equally; stock breaks the tie.
+	res, err = client.ResolveMPN(ctx, "lm358dr", "")
+	if err != nil {
+		t.Fatalf("ResolveMPN failed: %v", err)
+	}
+	if res.Code() != "C7951" || res.Confidence() != ConfidenceMedium || res.Best.BrandMatch != nil {
+		t.Errorf("expected medium-confidence match by stock, got %s %v", res.Code(), res.Confidence())
+	}
+
+	// A manufacturer that matches nothing lowers confidence.
+	res, err = client.ResolveMPN(ctx, "NE555", "Renesas")
+	if err != nil {
+		t.Fatalf("ResolveMPN failed: %v", err)
+	}
+	if res.Code() != "C1" || res.Confidence() != ConfidenceLow {
+		t.Errorf("expected low confidence for brand mismatch, got %s %v", res.Code(), res.Confidence())
+	}
+}
+
+// TestResolveMPNPackagingSuffix tests the fallback search without suffix.
+func TestResolveMPNPackagingSuffix(t *testing.T) {
+	server, keywords := newMPNServer(t,
+		Product{ComponentCode: "C6186", ComponentModelEn: "AMS1117-3.3", ComponentBrandEn: "AMS(Advanced Monolithic Systems)", StockCount: 100},
+	)
+	client := NewClient(WithBaseURL(server.URL))
+
+	res, err := client.ResolveMPN(context.Background(), "AMS1117-3.3-TR", "AMS")
+	if err != nil {
+		t.Fatalf("ResolveMPN failed: %v", err)
+	}
+	if got := keywords(); len(got) != 2 || got[1] != "AMS1117-3.3" {
+		t.Errorf("expected fallback search without suffix, got %v", got)
+	}
+	if res.Code() != "C6186" || res.Best.MPNMatch != "normalized" || res.Confidence() != ConfidenceHigh {
+		t.Errorf("unexpected resolution %s %+v", res.Code(), res.Best)
+	}
+}
+
+// TestResolveMPNNoMatch tests empty results and input validation.
+func TestResolveMPNNoMatch(t *testing.T) {
+	server, _ := newMPNServer(t, Product{ComponentCode: "C1", ComponentModelEn: "NE555"})
+	client := NewClient(WithBaseURL(server.URL))
+
+	res, err := client.ResolveMPN(context.Background(), "XYZ123", "")
+	if err != nil {
+		t.Fatalf("ResolveMPN failed: %v", err)
+	}
+	if res.Best != nil || res.Code() != "" || res.Confidence() != ConfidenceNone {
+		t.Errorf("expected no match, got %+v", res)
+	}
+
+	var invalid ErrInvalidInput
+	if _, err := client.ResolveMPN(context.Background(), " ", ""); !errors.As(err, &invalid) {
+		t.Errorf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+// TestConfidenceString tests confidence names and JSON encoding.
+func TestConfidenceString(t *testing.T) {
+	data, err := json.Marshal(map[string]Confidence{"c": ConfidenceHigh})
+	if err != nil || string(data) != `{"c":"high"}` {
+		t.Errorf("unexpected JSON %s: %v", data, err)
+	}
+	if ConfidenceNone.String() != "none" || ConfidenceLow.String() != "low" || ConfidenceMedium.String() != "medium" {
+		t.Error("unexpected confidence names")
+	}
+}
@@ -0,0 +1,122 @@
+package jlcpcb
+
+import (
+	"expvar"
+	"strconv"
+	"time"
+)
+
+// RequestInfo describes a single HTTP attempt.
+type RequestInfo struct {
+	Method   string        // HTTP method
+	Path     string        // Path relative to the base URL
+	Attempt  int           // Attempt number, starting at 0
+	Status   int           // HTTP status code, 0 if no response (RequestEnd only)
+	Duration time.Duration // Time spent on the attempt (RequestEnd only)
+	Err      error         // Error of the attempt, if any (RequestEnd only)
+}
+
+// RetryInfo describes a retry that is about to be made.
+type RetryInfo struct {
+	Method  string        // HTTP method
+	Path    string        // Path relative to the base URL
+	Attempt int           // Attempt number about to be made
+	Wait    time.Duration // Backoff before the attempt
+	Err     error         // Error of the previous attempt
+}
+
+// Observer interface defines callbacks for client activity.
+// Implementations must be safe for concurrent use. Embed NopObserver to
+// implement only some of the methods.
+type Observer interface {
+	RequestStart(info RequestInfo)
+	RequestEnd(info RequestInfo)
+	Retry(info RetryInfo)
+	CacheHit(key string)
+	CacheMiss(key string)
+	RateLimitWait(wait time.Duration)
+}
+
+// NopObserver is an Observer that ignores all events.
+type NopObserver struct{}
+
+// RequestStart implements Observer.
+func (NopObserver) RequestStart(RequestInfo) {}
+
+// RequestEnd implements Observer.
+func (NopObserver) RequestEnd(RequestInfo) {}
+
+// Retry implements Observer.
+func (NopObserver) Retry(RetryInfo) {}
+
+// CacheHit implements Observer.
+func (NopObserver) CacheHit(string) {}
+
+// CacheMiss implements Observer.
+func (NopObserver) CacheMiss(string) {}
+
+// RateLimitWait implements Observer.
+func (NopObserver) RateLimitWait(time.Duration) {}
+
+// ExpvarObserver is an Observer that maintains counters in an expvar.Map.
+//
+// Keys: requests, requests_in_flight, request_errors, request_duration_ns,
+// status_<code>, retries, retry_wait_ns, cache_hits, cache_misses,
+// rate_limit_waits and rate_limit_wait_ns.
+type ExpvarObserver struct {
+	vars *expvar.Map
+}
+
+// NewExpvarObserver creates an observer that records counters in vars.
+// Publish the map with expvar.NewMap to expose it on /debug/vars.
+func NewExpvarObserver(vars *expvar.Map) *ExpvarObserver {
+	return &ExpvarObserver{vars: vars}
+}
+
+// Vars returns the map the observer records into.
+func (eo *ExpvarObserver) Vars() *expvar.Map {
+	return eo.vars
+}
+
+// RequestStart implements Observer.
+func (eo *ExpvarObserver) RequestStart(info RequestInfo) {
+	eo.vars.Add("requests", 1)
+	eo.vars.Add("requests_in_flight", 1)
+}
+
+// RequestEnd implements Observer.
+func (eo *ExpvarObserver) RequestEnd(info RequestInfo) {
+	eo.vars.Add("requests_in_flight", -1)
+	eo.vars.Add("request_duration_ns", int64(info.Duration))
+	if info.Status != 0 {
+		eo.vars.Add("status_"+strconv.Itoa(info.Status), 1)
+	}
+	if info.Err != nil {
+		eo.vars.Add("request_errors", 1)
+	}
+}
+
+// Retry implements Observer.
+func (eo *ExpvarObserver) Retry(info RetryInfo) {
+	eo.vars.Add("retries", 1)
+	eo.vars.Add("retry_wait_ns", int64(info.Wait))
+}
+
+// CacheHit implements Observer.
+func (eo *ExpvarObserver) CacheHit(key string) {
+	eo.vars.Add("cache_hits", 1)
+}
+
+// CacheMiss implements Observer.
+func (eo *ExpvarObserver) CacheMiss(key string) {
+	eo.vars.Add("cache_misses", 1)
+}
+
+// RateLimitWait implements Observer.
+func (eo *ExpvarObserver) RateLimitWait(wait time.Duration) {
+	if wait <= 0 {
+		return
+	}
+	eo.vars.Add("rate_limit_waits", 1)
+	eo.vars.Add("rate_limit_wait_ns", int64(wait))
+}
@@ -0,0 +1,191 @@
+package jlcpcb
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recordingObserver records observer events for tests.
+type recordingObserver struct {
+	mu       sync.Mutex
+	starts   []RequestInfo
+	ends     []RequestInfo
+	retries  []RetryInfo
+	hits     []string
+	misses   []string
+	limiters int
+}
+
+func (ro *recordingObserver) RequestStart(info RequestInfo) {
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+	ro.starts = append(ro.starts, info)
+}
+
+func (ro *recordingObserver) RequestEnd(info RequestInfo) {
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+	ro.ends = append(ro.ends, info)
+}
+
+func (ro *recordingObserver) Retry(info RetryInfo) {
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+	ro.retries = append(ro.retries, info)
+}
+
+func (ro *recordingObserver) CacheHit(key string) {
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+	ro.hits = append(ro.hits, key)
+}
+
+func (ro *recordingObserver) CacheMiss(key string) {
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+	ro.misses = append(ro.misses, key)
+}
+
+func (ro *recordingObserver) RateLimitWait(wait time.Duration) {
+	ro.mu.Lock()
+	defer ro.mu.Unlock()
+	ro.limiters++
+}
+
+// newFlakyServer starts a test server that fails the first n requests with 503.
+func newFlakyServer(t *testing.T, n int32, products ...Product) *httptest.Server {
+	t.Helper()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= n {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var wrapper productSearchWrapper
+		wrapper.Code = 200
+		wrapper.Data.ComponentPageInfo = SearchResponse{Products: products, TotalCount: len(products)}
+		_ = json.NewEncoder(w).Encode(wrapper)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// fastRetryConfig returns a retry configuration with negligible backoff.
+func fastRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries:        3,
+		InitialBackoff:    time.Millisecond,
+		MaxBackoff:        time.Millisecond,
+		BackoffMultiplier: 1,
+	}
+}
+
+// TestObserverRequestAndRetryEvents tests request and retry callbacks.
+func TestObserverRequestAndRetryEvents(t *testing.T) {
+	server := newFlakyServer(t, 1, Product{ComponentCode: "C1"})
+	observer := &recordingObserver{}
+	client := NewClient(WithBaseURL(server.URL), WithRetryConfig(fastRetryConfig()), WithObserver(observer))
+
+	if _, err := client.KeywordSearch(context.Background(), SearchRequest{Keyword: "C1"}); err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+
+	if len(observer.starts) != 2 || len(observer.ends) != 2 {
+		t.Fatalf("expected 2 attempts, got %d starts and %d ends", len(observer.starts), len(observer.ends))
+	}
+	first, second := observer.ends[0], observer.ends[1]
+	if first.Status != http.StatusServiceUnavailable || first.Err == nil || first.Attempt != 0 {
+		t.Errorf("unexpected first attempt: %+v", first)
+	}
+	if second.Status != http.StatusOK || second.Err != nil || second.Attempt != 1 {
+		t.Errorf("unexpected second attempt: %+v", second)
+	}
+	if first.Method != http.MethodPost || first.Path != "/selectSmtComponentList/v2" {
+		t.Errorf("unexpected method/path: %s %s", first.Method, first.Path)
+	}
+
+	if len(observer.retries) != 1 || observer.retries[0].Attempt != 1 || observer.retries[0].Err == nil {
+		t.Errorf("unexpected retries: %+v", observer.retries)
+	}
+	if observer.limiters != 2 {
+		t.Errorf("expected 2 rate limiter waits, got %d", observer.limiters)
+	}
+}
+
+// TestObserverCacheEvents tests cache hit and miss callbacks.
+func TestObserverCacheEvents(t *testing.T) {
+	server := newSearchServer(t, Product{ComponentCode: "C1"})
+	observer := &recordingObserver{}
+	client := NewClient(WithBaseURL(server.URL), WithCache(NewMemoryCache()), WithObserver(observer))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.KeywordSearch(ctx, SearchRequest{Keyword: "C1"}); err != nil {
+			t.Fatalf("KeywordSearch failed: %v", err)
+		}
+	}
+
+	if len(observer.misses) != 1 || len(observer.hits) != 1 {
+		t.Errorf("expected 1 miss and 1 hit, got %d and %d", len(observer.misses), len(observer.hits))
+	}
+	if len(observer.starts) != 1 {
+		t.Errorf("expected 1 request, got %d", len(observer.starts))
+	}
+}
+
+// TestWithObserverNil tests that a nil observer is replaced by a no-op.
+func TestWithObserverNil(t *testing.T) {
+	client := NewClient(WithObserver(nil))
+
+	if _, ok := client.observer.(NopObserver); !ok {
+		t.Errorf("expected NopObserver, got %T", client.observer)
+	}
+}
+
+// TestExpvarObserver tests the expvar counters.
+func TestExpvarObserver(t *testing.T) {
+	server := newFlakyServer(t, 2, Product{ComponentCode: "C1"})
+	vars := new(expvar.Map)
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRetryConfig(fastRetryConfig()),
+		WithCache(NewMemoryCache()),
+		WithObserver(NewExpvarObserver(vars)),
+	)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.KeywordSearch(context.Background(), SearchRequest{Keyword: "C1"}); err != nil {
+			t.Fatalf("KeywordSearch failed: %v", err)
+		}
+	}
+
+	expected := map[string]string{
+		"requests":           "3",
+		"requests_in_flight": "0",
+		"request_errors":     "2",
+		"status_503":         "2",
+		"status_200":         "1",
+		"retries":            "2",
+		"cache_hits":         "1",
+		"cache_misses":       "1",
+	}
+	for key, want := range expected {
+		v := vars.Get(key)
+		if v == nil {
+			t.Errorf("missing counter %s", key)
+			continue
+		}
+		if v.String() != want {
+			t.Errorf("expected %s = %s, got %s", key, want, v.String())
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package jlcpcb
+
+import "context"
+
+// maxPageSize is the largest page the search API returns.
+const maxPageSize = 100
+
+// Pager pages through the results of a search, one request per page. It is
+// used like bufio.Scanner:
+//
+//	pager := jlcpcb.NewPager(client, req)
+//	for pager.Next(ctx) {
+//		for _, p := range pager.Page().Products {
+//			...
+//		}
+//	}
+//	if err := pager.Err(); err != nil {
+//		...
+//	}
+//
+// Only the current page is held in memory.
+type Pager struct {
+	api  PartsAPI
+	req  SearchRequest
+	page *SearchResponse
+	err  error
+	done bool
+}
+
+// NewPager returns a pager for req, starting at req.CurrentPage (default
+// 1). A PageSize outside 1 to 100 is replaced by 100, the largest page the
+// API returns, to minimize requests.
+func NewPager(api PartsAPI, req SearchRequest) *Pager {
+	if req.CurrentPage <= 0 {
+		req.CurrentPage = 1
+	}
+	if req.PageSize <= 0 || req.PageSize > maxPageSize {
+		req.PageSize = maxPageSize
+	}
+	req.CurrentPage--
+	return &Pager{api: api, req: req}
+}
+
+// Next fetches the next page and reports whether there is one. It returns
+// false when the results are exhausted or a request fails; check Err.
+func (pg *Pager) Next(ctx context.Context) bool {
+	if pg.done {
+		return false
+	}
+
+	pg.req.CurrentPage++
+	resp, err := pg.api.KeywordSearch(ctx, pg.req)
+	if err != nil {
+		pg.err, pg.page, pg.done = err, nil, true
+		return false
+	}
+	if len(resp.Products) == 0 {
+		pg.page, pg.done = nil, true
+		return false
+	}
+
+	pg.page = resp
+	if pg.req.CurrentPage*pg.req.PageSize >= resp.TotalCount {
+		// Last page: return it, then stop without another request.
+		pg.done = true
+	}
+	return true
+}
+
+// Page returns the page fetched by the last successful call to Next.
+func (pg *Pager) Page() *SearchResponse {
+	return pg.page
+}
+
+// PageNumber returns the number of the page last requested.
+func (pg *Pager) PageNumber() int {
+	return pg.req.CurrentPage
+}
+
+// PageSize returns the page size used for requests.
+func (pg *Pager) PageSize() int {
+	return pg.req.PageSize
+}
+
+// Err returns the error that stopped the pager, if any.
+func (pg *Pager) Err() error {
+	return pg.err
+}
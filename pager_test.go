@@ -0,0 +1,71 @@
+package jlcpcb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+	"github.com/PatrickWalther/go-jlcpcb-parts/jlcpcbtest"
+)
+
+// TestPager tests paging through every result without extra requests.
+func TestPager(t *testing.T) {
+	fake := jlcpcbtest.NewFake(jlcpcbtest.Fixtures()...)
+	req := jlcpcb.SearchRequest{Keyword: "0603", PageSize: 2}
+	pager := jlcpcb.NewPager(fake, req)
+
+	var codes []string
+	pages := 0
+	for pager.Next(context.Background()) {
+		pages++
+		if pager.PageNumber() != pages {
+			t.Errorf("expected page %d, got %d", pages, pager.PageNumber())
+		}
+		for _, p := range pager.Page().Products {
+			codes = append(codes, p.ComponentCode)
+		}
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatalf("pager failed: %v", err)
+	}
+
+	total := len(jlcpcbtest.Filter(jlcpcbtest.Fixtures(), req))
+	if total < 3 || len(codes) != total {
+		t.Errorf("expected %d products over several pages, got %d", total, len(codes))
+	}
+	if want := (total + 1) / 2; pages != want || len(fake.Searches()) != want {
+		t.Errorf("expected %d pages and requests, got %d pages and %d requests", want, pages, len(fake.Searches()))
+	}
+	if pager.Next(context.Background()) {
+		t.Error("expected exhausted pager to stay done")
+	}
+}
+
+// TestPagerDefaults tests the page size and start page defaults.
+func TestPagerDefaults(t *testing.T) {
+	pager := jlcpcb.NewPager(jlcpcbtest.NewFake(), jlcpcb.SearchRequest{Keyword: "x", PageSize: 500})
+	if pager.PageSize() != 100 {
+		t.Errorf("expected page size 100, got %d", pager.PageSize())
+	}
+	if pager.Next(context.Background()) {
+		t.Error("expected no pages for empty results")
+	}
+	if pager.Err() != nil || pager.Page() != nil {
+		t.Errorf("unexpected state after empty results: %v", pager.Err())
+	}
+}
+
+// TestPagerError tests that a failed request stops the pager.
+func TestPagerError(t *testing.T) {
+	fake := jlcpcbtest.NewFake(jlcpcbtest.Fixtures()...)
+	fake.SetError(jlcpcb.ErrRateLimited{})
+
+	pager := jlcpcb.NewPager(fake, jlcpcb.SearchRequest{Keyword: "resistor", CurrentPage: 3})
+	if pager.Next(context.Background()) {
+		t.Fatal("expected Next to fail")
+	}
+	if !errors.Is(pager.Err(), jlcpcb.ErrRateLimited{}) || pager.PageNumber() != 3 {
+		t.Errorf("unexpected error %v on page %d", pager.Err(), pager.PageNumber())
+	}
+}
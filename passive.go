@@ -0,0 +1,378 @@
+package jlcpcb
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PatrickWalther/go-jlcpcb-parts/internal/units"
+)
+
+// PassiveKind is the kind of a passive component.
+type PassiveKind string
+
+// Passive component kinds.
+const (
+	Resistor  PassiveKind = "resistor"
+	Capacitor PassiveKind = "capacitor"
+	Inductor  PassiveKind = "inductor"
+)
+
+// passiveKinds describes how each kind appears in the catalog.
+var passiveKinds = map[PassiveKind]struct {
+	category  string // Substring of FirstSortName
+	attribute string // Attribute holding the value
+	unit      string // Unit symbol of the value
+}{
+	Resistor:  {"Resistor", "Resistance", "Ω"},
+	Capacitor: {"Capacitor", "Capacitance", "F"},
+	Inductor:  {"Inductor", "Inductance", "H"},
+}
+
+// PassiveSpec is a passive component described in free text, such as
+// "10k 0402" or "100n 0603 X7R 50V".
+type PassiveSpec struct {
+	Kind         PassiveKind
+	Value        float64  // In ohms, farads or henries
+	Package      string   // Imperial size code, e.g. "0402"; empty if not given
+	Dielectric   string   // Capacitor dielectric, e.g. "X7R" or "C0G"; empty if not given
+	MinVoltage   float64  // Minimum rated voltage; 0 if not given
+	MaxTolerance float64  // Maximum tolerance in percent; 0 if not given
+	Ignored      []string // Words that were not understood
+}
+
+// String formats the spec, e.g. "resistor 10kΩ 0402".
+func (s PassiveSpec) String() string {
+	parts := []string{string(s.Kind), formatSI(s.Value) + passiveKinds[s.Kind].unit}
+	if s.Package != "" {
+		parts = append(parts, s.Package)
+	}
+	if s.Dielectric != "" {
+		parts = append(parts, s.Dielectric)
+	}
+	if s.MinVoltage > 0 {
+		parts = append(parts, formatSI(s.MinVoltage)+"V")
+	}
+	if s.MaxTolerance > 0 {
+		parts = append(parts, "±"+strconv.FormatFloat(s.MaxTolerance, 'f', -1, 64)+"%")
+	}
+	return strings.Join(parts, " ")
+}
+
+// packagePattern matches an imperial chip size, alone or inside a
+// footprint name such as "R_0402_1005Metric".
+var packagePattern = regexp.MustCompile(`(?:^|[^0-9])(01005|0201|0402|0603|0805|1206|1210|1812|2010|2512)(?:[^0-9]|$)`)
+
+// dielectrics maps dielectric spellings to the catalog's names.
+var dielectrics = map[string]string{
+	"X5R": "X5R", "X6S": "X6S", "X7R": "X7R", "X7S": "X7S", "X8R": "X8R", "Y5V": "Y5V",
+	"C0G": "C0G", "COG": "C0G", "NP0": "C0G", "NPO": "C0G",
+}
+
+// kindWords maps words naming a kind of passive.
+var kindWords = map[string]PassiveKind{
+	"r": Resistor, "res": Resistor, "resistor": Resistor, "ohm": Resistor, "ohms": Resistor,
+	"c": Capacitor, "cap": Capacitor, "capacitor": Capacitor, "mlcc": Capacitor,
+	"l": Inductor, "ind": Inductor, "inductor": Inductor,
+}
+
+// ParsePassive parses a free-text passive description, such as a BOM
+// comment. Values use SI prefixes with an optional unit ("10k", "4k7",
+// "100nF", "2.2uH"); a bare value below one milli is taken as a
+// capacitance, as in "100n", and any other bare value as a resistance.
+// Words like "cap" or "resistor" override that guess. Package sizes,
+// dielectrics ("X7R", "NP0"), voltages ("50V") and tolerances ("1%") are
+// recognized anywhere in the text.
+func ParsePassive(text string) (PassiveSpec, error) {
+	var spec PassiveSpec
+	var kind PassiveKind
+	found := false
+
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == ',' || r == ';'
+	})
+	for _, word := range words {
+		upper := strings.ToUpper(word)
+		if k, ok := kindWords[strings.ToLower(word)]; ok {
+			kind = k
+			continue
+		}
+		if d, ok := dielectrics[upper]; ok {
+			spec.Dielectric = d
+			continue
+		}
+		if strings.HasSuffix(word, "%") {
+			if tol, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimLeft(word, "±+-"), "%"), 64); err == nil {
+				spec.MaxTolerance = tol
+				continue
+			}
+		}
+		if m := packagePattern.FindStringSubmatch(word); m != nil {
+			spec.Package = m[1]
+			continue
+		}
+
+		value, unit, ok := units.Parse(word)
+		switch {
+		case ok && unit == "V":
+			spec.MinVoltage = value
+		case ok && !found && (unit == "" || unit == "Ω" || unit == "R"):
+			spec.Value, found = value, true
+			if unit == "" && value < 1e-3 {
+				spec.Kind = Capacitor
+			} else {
+				spec.Kind = Resistor
+			}
+		case ok && !found && unit == "F":
+			spec.Value, spec.Kind, found = value, Capacitor, true
+		case ok && !found && unit == "H":
+			spec.Value, spec.Kind, found = value, Inductor, true
+		default:
+			spec.Ignored = append(spec.Ignored, word)
+		}
+	}
+
+	if !found {
+		return spec, ErrInvalidInput{Message: fmt.Sprintf("no component value in %q", text)}
+	}
+	if kind != "" {
+		spec.Kind = kind
+	}
+	return spec, nil
+}
+
+// PassiveOptions configures ResolvePassive.
+type PassiveOptions struct {
+	Quantity  int  // Order quantity for stock and price checks (default 1)
+	BasicOnly bool // Reject preferred extended parts
+}
+
+// PassiveCandidate is a part considered by ResolvePassive.
+type PassiveCandidate struct {
+	Product   *Product
+	UnitPrice Money    // Unit price at the order quantity, if priced
+	Accepted  bool     // Whether the part satisfies the spec
+	Reasons   []string // Why the part was chosen, passed over or rejected
+}
+
+// PassiveResolution is the result of ResolvePassive.
+type PassiveResolution struct {
+	Spec       PassiveSpec
+	Best       *PassiveCandidate  // Cheapest accepted candidate; nil if none
+	Candidates []PassiveCandidate // Accepted candidates by price, then rejected ones
+}
+
+// Code returns the LCSC code of the chosen part, or "" if none.
+func (r *PassiveResolution) Code() string {
+	if r.Best == nil {
+		return ""
+	}
+	return r.Best.Product.ComponentCode
+}
+
+// passiveSearchSize is the number of results searched per library.
+const passiveSearchSize = 50
+
+// ResolvePassive parses a free-text passive description (see ParsePassive)
+// and picks the cheapest in-stock matching part from JLCPCB's basic
+// library, or from preferred extended parts, which also carry no loading
+// fee. Every part found is returned with the reasons it was chosen, passed
+// over or rejected.
+func (c *Client) ResolvePassive(ctx context.Context, text string, opts PassiveOptions) (*PassiveResolution, error) {
+	spec, err := ParsePassive(text)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Quantity <= 0 {
+		opts.Quantity = 1
+	}
+
+	req := SearchRequest{
+		Keyword:   formatSI(spec.Value) + passiveKinds[spec.Kind].unit,
+		PageSize:  passiveSearchSize,
+		StockOnly: true,
+	}
+	if spec.Package != "" {
+		req.Packages = []string{spec.Package}
+	}
+
+	// Basic parts first, then preferred extended parts, each searched with
+	// its own filter so neither crowds the other out of the page.
+	passes := []SearchRequest{req, req}
+	passes[0].ComponentType = "base"
+	passes[1].PreferredOnly = true
+	if opts.BasicOnly {
+		passes = passes[:1]
+	}
+
+	seen := make(map[string]bool)
+	var candidates []PassiveCandidate
+	for _, req := range passes {
+		resp, err := c.KeywordSearch(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for i := range resp.Products {
+			p := &resp.Products[i]
+			if seen[p.ComponentCode] {
+				continue
+			}
+			seen[p.ComponentCode] = true
+			candidates = append(candidates, evaluatePassive(p, spec, opts))
+		}
+	}
+
+	return rankPassives(spec, candidates), nil
+}
+
+// evaluatePassive checks a part against a spec.
+func evaluatePassive(p *Product, spec PassiveSpec, opts PassiveOptions) PassiveCandidate {
+	cand := PassiveCandidate{Product: p, Accepted: true}
+	reject := func(format string, args ...interface{}) {
+		cand.Accepted = false
+		cand.Reasons = append(cand.Reasons, fmt.Sprintf(format, args...))
+	}
+	kind := passiveKinds[spec.Kind]
+
+	if !strings.Contains(strings.ToLower(p.FirstSortName), strings.ToLower(kind.category)) {
+		reject("category %q is not a %s", p.FirstSortName, spec.Kind)
+	}
+
+	if value, ok := attributeValue(p, kind.attribute); !ok {
+		reject("no %s attribute", kind.attribute)
+	} else if got, _, ok := units.Parse(value); !ok || !units.Equal(got, spec.Value) {
+		reject("%s %s does not match %s%s", kind.attribute, value, formatSI(spec.Value), kind.unit)
+	}
+
+	if spec.Package != "" && !strings.HasPrefix(p.ComponentSpecificationEn, spec.Package) {
+		reject("package %s does not match %s", p.ComponentSpecificationEn, spec.Package)
+	}
+
+	if spec.Dielectric != "" {
+		value, _ := attributeValue(p, "Temperature Coefficient")
+		if dielectrics[strings.ToUpper(strings.TrimSpace(value))] != spec.Dielectric {
+			reject("dielectric %q is not %s", value, spec.Dielectric)
+		}
+	}
+
+	if spec.MinVoltage > 0 {
+		value, _ := attributeValue(p, "Voltage Rated")
+		if got, unit, ok := units.Parse(value); !ok || unit != "V" || got < spec.MinVoltage {
+			reject("rated voltage %q is below %sV", value, formatSI(spec.MinVoltage))
+		}
+	}
+
+	if spec.MaxTolerance > 0 {
+		value, _ := attributeValue(p, "Tolerance")
+		got, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimLeft(value, "±+-"), "%"), 64)
+		if err != nil || got > spec.MaxTolerance {
+			reject("tolerance %q is wider than ±%s%%", value, strconv.FormatFloat(spec.MaxTolerance, 'f', -1, 64))
+		}
+	}
+
+	switch {
+	case p.IsBasic():
+	case p.IsPreferred() && !opts.BasicOnly:
+	case p.IsPreferred():
+		reject("preferred extended part, but only basic parts are allowed")
+	default:
+		reject("extended part with a loading fee")
+	}
+
+	if p.StockCount < opts.Quantity {
+		reject("only %d in stock, need %d", p.StockCount, opts.Quantity)
+	}
+
+	if price, ok := p.UnitPrice(opts.Quantity); ok {
+		cand.UnitPrice = price
+	} else {
+		reject("no price")
+	}
+	return cand
+}
+
+// rankPassives orders candidates, picks the best and explains the choice.
+func rankPassives(spec PassiveSpec, candidates []PassiveCandidate) *PassiveResolution {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Accepted != b.Accepted {
+			return a.Accepted
+		}
+		if !a.Accepted {
+			return false
+		}
+		if cmp := a.UnitPrice.Cmp(b.UnitPrice); cmp != 0 {
+			return cmp < 0
+		}
+		if a.Product.IsBasic() != b.Product.IsBasic() {
+			return a.Product.IsBasic()
+		}
+		return a.Product.StockCount > b.Product.StockCount
+	})
+
+	res := &PassiveResolution{Spec: spec, Candidates: candidates}
+	if len(candidates) == 0 || !candidates[0].Accepted {
+		return res
+	}
+
+	res.Best = &res.Candidates[0]
+	best := res.Best
+	best.Reasons = append(best.Reasons, fmt.Sprintf("matches %s", spec), fmt.Sprintf("%s, cheapest at %s", libraryName(best.Product), best.UnitPrice))
+	for i := 1; i < len(candidates) && candidates[i].Accepted; i++ {
+		cand := &res.Candidates[i]
+		if cand.UnitPrice.Cmp(best.UnitPrice) == 0 {
+			cand.Reasons = append(cand.Reasons, fmt.Sprintf("%s, same price as %s but ranked lower", libraryName(cand.Product), best.Product.ComponentCode))
+		} else {
+			cand.Reasons = append(cand.Reasons, fmt.Sprintf("%s, costs %s, more than %s", libraryName(cand.Product), cand.UnitPrice, best.Product.ComponentCode))
+		}
+	}
+	return res
+}
+
+// libraryName describes a part's library for explanations.
+func libraryName(p *Product) string {
+	switch {
+	case p.IsBasic():
+		return "basic part"
+	case p.IsPreferred():
+		return "preferred extended part"
+	default:
+		return "extended part"
+	}
+}
+
+// attributeValue returns the value of a product attribute by name.
+func attributeValue(p *Product, name string) (string, bool) {
+	for _, attr := range p.Attributes {
+		if strings.EqualFold(attr.Name, name) {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
+
+// formatSI formats a value with an SI prefix as JLCPCB writes attributes,
+// e.g. 10000 as "10k" and 1e-7 as "100n".
+func formatSI(v float64) string {
+	if v == 0 {
+		return "0"
+	}
+	prefixes := []struct {
+		scale  float64
+		prefix string
+	}{
+		{1e9, "G"}, {1e6, "M"}, {1e3, "k"}, {1, ""}, {1e-3, "m"}, {1e-6, "u"}, {1e-9, "n"}, {1e-12, "p"},
+	}
+	for _, p := range prefixes {
+		if math.Abs(v) >= p.scale*(1-1e-9) {
+			n := math.Round(v/p.scale*1e6) / 1e6
+			return strconv.FormatFloat(n, 'f', -1, 64) + p.prefix
+		}
+	}
+	n := math.Round(v/1e-12*1e6) / 1e6
+	return strconv.FormatFloat(n, 'f', -1, 64) + "p"
+}
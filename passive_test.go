@@ -0,0 +1,182 @@
+package jlcpcb_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+	"github.com/PatrickWalther/go-jlcpcb-parts/jlcpcbtest"
+)
+
+// passiveResistor returns a 0402 resistor fixture with the given library,
+// resistance and unit price.
+func passiveResistor(t *testing.T, code, library string, preferred bool, resistance, price string) jlcpcb.Product {
+	t.Helper()
+
+	money, err := jlcpcb.ParseMoney(price, "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jlcpcb.Product{
+		ComponentCode:            code,
+		ComponentModelEn:         "TEST-" + code,
+		ComponentSpecificationEn: "0402",
+		StockCount:               100000,
+		FirstSortName:            "Resistors",
+		ComponentLibraryType:     library,
+		PreferredComponentFlag:   jlcpcb.FlexBool(preferred),
+		ComponentPrices:          []jlcpcb.PriceBreak{{StartNumber: 1, EndNumber: -1, ProductPrice: money}},
+		Attributes:               []jlcpcb.Attribute{{Name: "Resistance", Value: resistance}},
+	}
+}
+
+// TestParsePassive tests parsing free-text passive descriptions.
+func TestParsePassive(t *testing.T) {
+	tests := []struct {
+		text string
+		want jlcpcb.PassiveSpec
+	}{
+		{"10k 0402", jlcpcb.PassiveSpec{Kind: jlcpcb.Resistor, Value: 10e3, Package: "0402"}},
+		{"4k7 1% R_0603_1608Metric", jlcpcb.PassiveSpec{Kind: jlcpcb.Resistor, Value: 4.7e3, Package: "0603", MaxTolerance: 1}},
+		{"100n 0603 X7R", jlcpcb.PassiveSpec{Kind: jlcpcb.Capacitor, Value: 100e-9, Package: "0603", Dielectric: "X7R"}},
+		{"22pF NP0 50V 0402", jlcpcb.PassiveSpec{Kind: jlcpcb.Capacitor, Value: 22e-12, Package: "0402", Dielectric: "C0G", MinVoltage: 50}},
+		{"2.2uH 0805", jlcpcb.PassiveSpec{Kind: jlcpcb.Inductor, Value: 2.2e-6, Package: "0805"}},
+		{"cap 10 1206", jlcpcb.PassiveSpec{Kind: jlcpcb.Capacitor, Value: 10, Package: "1206"}},
+	}
+
+	for _, tt := range tests {
+		got, err := jlcpcb.ParsePassive(tt.text)
+		if err != nil {
+			t.Errorf("ParsePassive(%q) failed: %v", tt.text, err)
+			continue
+		}
+		if got.Kind != tt.want.Kind || got.Package != tt.want.Package || got.Dielectric != tt.want.Dielectric ||
+			got.MinVoltage != tt.want.MinVoltage || got.MaxTolerance != tt.want.MaxTolerance ||
+			got.Value < tt.want.Value*(1-1e-9) || got.Value > tt.want.Value*(1+1e-9) {
+			t.Errorf("ParsePassive(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+
+	var inputErr jlcpcb.ErrInvalidInput
+	if _, err := jlcpcb.ParsePassive("0402 X7R"); !errors.As(err, &inputErr) {
+		t.Errorf("expected ErrInvalidInput without a value, got %v", err)
+	}
+}
+
+// TestResolvePassive tests picking basic fixture parts.
+func TestResolvePassive(t *testing.T) {
+	server := jlcpcbtest.NewServer(jlcpcbtest.Fixtures())
+	defer server.Close()
+	client := jlcpcb.NewClient(jlcpcb.WithBaseURL(server.URL), jlcpcb.WithRateLimit(1000))
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"10k 0402", "C25744"},
+		{"10K 0603", "C25804"},
+		{"100n 0402 X7R 16V", "C1525"},
+		{"100nF 0603 50V", "C14663"},
+		{"100n 0402 50V", ""},
+	}
+
+	for _, tt := range tests {
+		res, err := client.ResolvePassive(context.Background(), tt.text, jlcpcb.PassiveOptions{Quantity: 100})
+		if err != nil {
+			t.Errorf("ResolvePassive(%q) failed: %v", tt.text, err)
+			continue
+		}
+		if res.Code() != tt.want {
+			t.Errorf("ResolvePassive(%q) = %q, want %q", tt.text, res.Code(), tt.want)
+		}
+		if tt.want == "" && len(res.Candidates) > 0 && len(res.Candidates[0].Reasons) == 0 {
+			t.Errorf("ResolvePassive(%q): expected rejection reasons", tt.text)
+		}
+	}
+}
+
+// TestResolvePassiveRanking tests choosing the cheapest accepted part and
+// explaining every candidate.
+func TestResolvePassiveRanking(t *testing.T) {
+	// C104 only matches the search through its description.
+	mismatched := passiveResistor(t, "C104", "base", false, "1kΩ", "0.0001")
+	mismatched.Describe = "1kΩ resistor, drop-in for 10kΩ"
+
+	server := jlcpcbtest.NewServer([]jlcpcb.Product{
+		passiveResistor(t, "C100", "base", false, "10kΩ", "0.002"),
+		passiveResistor(t, "C101", "expand", true, "10kΩ", "0.001"),
+		passiveResistor(t, "C102", "expand", false, "10kΩ", "0.0001"),
+		passiveResistor(t, "C103", "base", false, "10kΩ", "0.001"),
+		mismatched,
+	})
+	defer server.Close()
+	client := jlcpcb.NewClient(jlcpcb.WithBaseURL(server.URL), jlcpcb.WithRateLimit(1000))
+
+	res, err := client.ResolvePassive(context.Background(), "10k 0402", jlcpcb.PassiveOptions{})
+	if err != nil {
+		t.Fatalf("ResolvePassive failed: %v", err)
+	}
+	if res.Code() != "C103" {
+		t.Fatalf("expected basic C103 to win the tie, got %q", res.Code())
+	}
+
+	reasons := make(map[string]string)
+	accepted := make(map[string]bool)
+	for _, cand := range res.Candidates {
+		reasons[cand.Product.ComponentCode] = strings.Join(cand.Reasons, "; ")
+		accepted[cand.Product.ComponentCode] = cand.Accepted
+	}
+	if len(reasons) != 4 {
+		t.Fatalf("expected 4 candidates, got %d", len(reasons))
+	}
+	if !accepted["C101"] || !strings.Contains(reasons["C101"], "same price") {
+		t.Errorf("expected preferred C101 to tie, got %q", reasons["C101"])
+	}
+	if !accepted["C100"] || !strings.Contains(reasons["C100"], "more than C103") {
+		t.Errorf("expected C100 to cost more, got %q", reasons["C100"])
+	}
+	if _, ok := reasons["C102"]; ok {
+		t.Errorf("expected extended C102 not to be searched, got %q", reasons["C102"])
+	}
+	if accepted["C104"] || !strings.Contains(reasons["C104"], "does not match") {
+		t.Errorf("expected 1kΩ C104 to be rejected, got %q", reasons["C104"])
+	}
+
+	res, err = client.ResolvePassive(context.Background(), "10k 0402", jlcpcb.PassiveOptions{BasicOnly: true, Quantity: 200000})
+	if err != nil {
+		t.Fatalf("ResolvePassive failed: %v", err)
+	}
+	if res.Best != nil || len(res.Candidates) != 3 {
+		t.Errorf("expected 3 understocked basic candidates, got %q of %d", res.Code(), len(res.Candidates))
+	}
+}
+
+// TestResolvePassivePreferredSearch tests that preferred parts are searched
+// with the preferred filter, so extended parts cannot crowd them out.
+func TestResolvePassivePreferredSearch(t *testing.T) {
+	var products []jlcpcb.Product
+	for i := 0; i < 60; i++ {
+		products = append(products, passiveResistor(t, fmt.Sprintf("C2%02d", i), "expand", false, "10kΩ", "0.0001"))
+	}
+	products = append(products, passiveResistor(t, "C300", "expand", true, "10kΩ", "0.001"))
+
+	server := jlcpcbtest.NewServer(products)
+	defer server.Close()
+	client := jlcpcb.NewClient(jlcpcb.WithBaseURL(server.URL), jlcpcb.WithRateLimit(1000))
+
+	res, err := client.ResolvePassive(context.Background(), "10k 0402", jlcpcb.PassiveOptions{})
+	if err != nil {
+		t.Fatalf("ResolvePassive failed: %v", err)
+	}
+	if res.Code() != "C300" || len(res.Candidates) != 1 {
+		t.Errorf("expected only preferred C300, got %q of %d", res.Code(), len(res.Candidates))
+	}
+
+	requests := server.Requests()
+	if len(requests) != 2 || requests[0].ComponentType != "base" || !requests[1].PreferredOnly {
+		t.Errorf("expected a basic and a preferred search, got %+v", requests)
+	}
+}
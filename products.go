@@ -4,6 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
 	"strings"
 	"time"
 )
@@ -24,15 +28,20 @@ type searchRequestBody struct {
 	SecondSortName             interface{}   `json:"secondSortName"`
 	SearchSource               string        `json:"searchSource"` // "search"
 	StockFlag                  bool          `json:"stockFlag"`
+	PreferredComponentFlag     bool          `json:"preferredComponentFlag"`
 }
 
 // KeywordSearch searches for products by keyword with optional filters.
 // Uses POST /selectSmtComponentList/v2 endpoint.
+// Concurrent identical searches share a single API request.
 func (c *Client) KeywordSearch(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
 	keyword := strings.TrimSpace(req.Keyword)
 	if keyword == "" {
 		return nil, fmt.Errorf("keyword is required")
 	}
+	if err := c.checkCurrency(); err != nil {
+		return nil, err
+	}
 
 	if req.CurrentPage <= 0 {
 		req.CurrentPage = 1
@@ -44,15 +53,43 @@ func (c *Client) KeywordSearch(ctx context.Context, req SearchRequest) (*SearchR
 		req.PageSize = 100
 	}
 
-	cacheKey := c.getCacheKeySearch(keyword, req.CurrentPage, req.PageSize)
+	ctx, logger := c.withRequestLogger(ctx)
+
+	cacheKey := c.getCacheKeySearch(keyword, req)
 	if c.cache != nil {
 		if cached, ok := c.cache.Get(cacheKey); ok {
 			var resp SearchResponse
 			if err := json.Unmarshal(cached, &resp); err == nil {
+				c.noteCache(ctx, logger, cacheKey, true)
 				return &resp, nil
 			}
 		}
+		c.noteCache(ctx, logger, cacheKey, false)
+	}
+
+	v, err, shared := c.flights.do(ctx, cacheKey, func(ctx context.Context) (interface{}, error) {
+		return c.fetchSearch(ctx, keyword, req, cacheKey)
+	})
+	if shared {
+		logger.DebugContext(ctx, "search coalesced", c.keywordAttr("keyword", keyword))
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Give each caller its own copy of the shared response.
+	resp := *v.(*SearchResponse)
+	resp.Products = make([]Product, len(resp.Products))
+	for i := range resp.Products {
+		resp.Products[i] = v.(*SearchResponse).Products[i].clone()
+	}
+	return &resp, nil
+}
+
+// fetchSearch performs a search request and caches the response.
+func (c *Client) fetchSearch(ctx context.Context, keyword string, req SearchRequest, cacheKey string) (*SearchResponse, error) {
+	ctx, logger := c.withRequestLogger(ctx)
+	start := time.Now()
 
 	// Build attribute filters
 	attrList := []interface{}{}
@@ -69,6 +106,12 @@ func (c *Client) KeywordSearch(ctx context.Context, req SearchRequest) (*SearchR
 		brandList = append(brandList, brand)
 	}
 
+	// Build package filters
+	specList := []interface{}{}
+	for _, pkg := range req.Packages {
+		specList = append(specList, pkg)
+	}
+
 	// Determine presale type
 	presaleType := "stock"
 	if req.PresaleType != "" {
@@ -90,12 +133,13 @@ func (c *Client) KeywordSearch(ctx context.Context, req SearchRequest) (*SearchR
 		ComponentLibraryType:       componentLibType,
 		ComponentAttributeList:     attrList,
 		ComponentBrandList:         brandList,
-		ComponentSpecificationList: []interface{}{},
+		ComponentSpecificationList: specList,
 		ParamList:                  []interface{}{},
 		FirstSortName:              req.SortBy,
 		SecondSortName:             req.SortBySecondary,
 		SearchSource:               "search",
 		StockFlag:                  req.StockOnly,
+		PreferredComponentFlag:     req.PreferredOnly,
 	})
 	if err != nil {
 		return nil, err
@@ -103,6 +147,14 @@ func (c *Client) KeywordSearch(ctx context.Context, req SearchRequest) (*SearchR
 
 	var wrapper productSearchWrapper
 	if err := c.parseResponse(body, &wrapper); err != nil {
+		if _, ok := err.(ErrRateLimited); ok {
+			// The API can also signal throttling inside a 200 response.
+			c.limiterFeedback(http.StatusTooManyRequests)
+		}
+		logger.WarnContext(ctx, "search failed",
+			c.keywordAttr("keyword", keyword),
+			slog.Int("page", req.CurrentPage),
+			slog.Any("error", err))
 		return nil, err
 	}
 
@@ -112,6 +164,19 @@ func (c *Client) KeywordSearch(ctx context.Context, req SearchRequest) (*SearchR
 		PageSize:   wrapper.Data.ComponentPageInfo.PageSize,
 		PageNumber: wrapper.Data.ComponentPageInfo.PageNumber,
 	}
+	if err := c.localizeProducts(resp.Products); err != nil {
+		return nil, err
+	}
+
+	logger.InfoContext(ctx, "search completed",
+		c.keywordAttr("keyword", keyword),
+		slog.Int("page", req.CurrentPage),
+		slog.Int("page_size", req.PageSize),
+		slog.Int("results", len(resp.Products)),
+		slog.Int("total", resp.TotalCount),
+		slog.Duration("latency", time.Since(start)))
+
+	c.recordSnapshots(resp.Products)
 
 	if c.cache != nil {
 		if cacheData, err := json.Marshal(resp); err == nil {
@@ -123,23 +188,50 @@ func (c *Client) KeywordSearch(ctx context.Context, req SearchRequest) (*SearchR
 }
 
 // GetProductDetails retrieves detailed information for a specific product.
-// Uses search endpoint to find product by part code
+// Uses search endpoint to find product by part code.
+// Concurrent lookups of the same part share a single API request.
 func (c *Client) GetProductDetails(ctx context.Context, partCode string) (*Product, error) {
 	partCode = strings.TrimSpace(partCode)
 	if partCode == "" {
 		return nil, fmt.Errorf("part code is required")
 	}
+	if err := c.checkCurrency(); err != nil {
+		return nil, err
+	}
+
+	ctx, logger := c.withRequestLogger(ctx)
 
 	cacheKey := c.getCacheKeyProduct(partCode)
 	if c.cache != nil {
 		if cached, ok := c.cache.Get(cacheKey); ok {
 			var product Product
 			if err := json.Unmarshal(cached, &product); err == nil {
+				c.noteCache(ctx, logger, cacheKey, true)
 				return &product, nil
 			}
 		}
+		c.noteCache(ctx, logger, cacheKey, false)
+	}
+
+	v, err, shared := c.flights.do(ctx, cacheKey, func(ctx context.Context) (interface{}, error) {
+		return c.fetchProduct(ctx, partCode, cacheKey)
+	})
+	if shared {
+		logger.DebugContext(ctx, "product lookup coalesced", c.keywordAttr("part_code", partCode))
+	}
+	if err != nil {
+		return nil, err
 	}
 
+	// Give each caller its own copy of the shared product.
+	product := v.(*Product).clone()
+	return &product, nil
+}
+
+// fetchProduct looks up a product by part code and caches it.
+func (c *Client) fetchProduct(ctx context.Context, partCode, cacheKey string) (*Product, error) {
+	ctx, logger := c.withRequestLogger(ctx)
+
 	// Search for the product by part code
 	resp, err := c.KeywordSearch(ctx, SearchRequest{
 		Keyword:     partCode,
@@ -150,8 +242,10 @@ func (c *Client) GetProductDetails(ctx context.Context, partCode string) (*Produ
 		return nil, err
 	}
 
-	if resp == nil || len(resp.Products) == 0 {
-		return nil, fmt.Errorf("product not found: %s", partCode)
+	// The search may return a different part when the code does not exist.
+	if resp == nil || len(resp.Products) == 0 || !strings.EqualFold(resp.Products[0].ComponentCode, partCode) {
+		logger.DebugContext(ctx, "product not found", c.keywordAttr("part_code", partCode))
+		return nil, ErrProductNotFound{ProductCode: partCode}
 	}
 
 	product := &resp.Products[0]
@@ -165,12 +259,51 @@ func (c *Client) GetProductDetails(ctx context.Context, partCode string) (*Produ
 	return product, nil
 }
 
-// getCacheKeySearch generates a cache key for search requests.
-func (c *Client) getCacheKeySearch(keyword string, page, pageSize int) string {
-	return fmt.Sprintf("search:%s:%s:%d:%d", c.currency, keyword, page, pageSize)
+// getCacheKeySearch generates a cache key for search requests. Filters are
+// included in a canonical order so that equivalent requests share a key.
+func (c *Client) getCacheKeySearch(keyword string, req SearchRequest) string {
+	key := fmt.Sprintf("search:%s:%s:%d:%d", c.currency, keyword, req.CurrentPage, req.PageSize)
+
+	filters := url.Values{}
+	if req.PresaleType != "" && req.PresaleType != "stock" {
+		filters.Set("presale", req.PresaleType)
+	}
+	if req.ComponentType != "" {
+		filters.Set("type", req.ComponentType)
+	}
+	for _, attr := range req.Attributes {
+		filters.Add("attr", attr.Name+"="+attr.Value)
+	}
+	for _, brand := range req.Brands {
+		filters.Add("brand", brand)
+	}
+	for _, pkg := range req.Packages {
+		filters.Add("package", pkg)
+	}
+	if req.StockOnly {
+		filters.Set("stock", "1")
+	}
+	if req.PreferredOnly {
+		filters.Set("preferred", "1")
+	}
+	if req.SortBy != "" {
+		filters.Set("sort", req.SortBy)
+	}
+	if req.SortBySecondary != "" {
+		filters.Set("sort2", req.SortBySecondary)
+	}
+	for _, values := range filters {
+		sort.Strings(values)
+	}
+
+	if len(filters) > 0 {
+		key += "?" + filters.Encode()
+	}
+	return key
 }
 
 // getCacheKeyProduct generates a cache key for product detail requests.
+// Part codes are case-insensitive.
 func (c *Client) getCacheKeyProduct(sku string) string {
-	return fmt.Sprintf("product:%s:%s", c.currency, sku)
+	return fmt.Sprintf("product:%s:%s", c.currency, strings.ToUpper(sku))
 }
@@ -0,0 +1,159 @@
+package jlcpcb_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+	"github.com/PatrickWalther/go-jlcpcb-parts/jlcpcbtest"
+)
+
+// newFakeServerClient starts a fake JLCPCB server and a client pointed at it.
+func newFakeServerClient(t *testing.T, opts ...jlcpcb.ClientOption) (*jlcpcbtest.Server, *jlcpcb.Client) {
+	t.Helper()
+
+	server := jlcpcbtest.NewServer(jlcpcbtest.Fixtures())
+	t.Cleanup(server.Close)
+
+	opts = append([]jlcpcb.ClientOption{
+		jlcpcb.WithBaseURL(server.URL),
+		jlcpcb.WithRateLimit(1000),
+		jlcpcb.WithRetryConfig(jlcpcb.RetryConfig{
+			MaxRetries:        3,
+			InitialBackoff:    time.Millisecond,
+			MaxBackoff:        time.Millisecond,
+			BackoffMultiplier: 1,
+		}),
+	}, opts...)
+
+	return server, jlcpcb.NewClient(opts...)
+}
+
+// TestKeywordSearchFakeServer tests search parsing against the fake server.
+func TestKeywordSearchFakeServer(t *testing.T) {
+	_, client := newFakeServerClient(t)
+
+	resp, err := client.KeywordSearch(context.Background(), jlcpcb.SearchRequest{Keyword: "resistors"})
+	if err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+
+	if resp.TotalCount != 2 || len(resp.Products) != 2 {
+		t.Fatalf("expected 2 resistors, got total %d", resp.TotalCount)
+	}
+
+	p := resp.Products[0]
+	if p.ComponentCode != "C25744" || p.ComponentSpecificationEn != "0402" {
+		t.Errorf("unexpected product: %+v", p)
+	}
+	if len(p.ComponentPrices) != 3 || p.ComponentPrices[1].ProductPrice.Decimal() != "0.0004" {
+		t.Errorf("expected string and numeric prices to decode, got %+v", p.ComponentPrices)
+	}
+	if len(p.Attributes) == 0 || p.Attributes[0].Name != "Resistance" {
+		t.Errorf("expected attributes to decode, got %+v", p.Attributes)
+	}
+}
+
+// TestKeywordSearchFiltersFakeServer tests that filters reach the server.
+func TestKeywordSearchFiltersFakeServer(t *testing.T) {
+	server, client := newFakeServerClient(t)
+
+	resp, err := client.KeywordSearch(context.Background(), jlcpcb.SearchRequest{
+		Keyword:       "capacitors",
+		PresaleType:   "stock",
+		ComponentType: "base",
+		Packages:      []string{"0603"},
+		Attributes:    []jlcpcb.FilterAttribute{{Name: "Capacitance", Value: "1uF"}},
+	})
+	if err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+
+	if resp.TotalCount != 1 || resp.Products[0].ComponentCode != "C15849" {
+		t.Errorf("expected C15849, got %+v", resp.Products)
+	}
+
+	req := server.Requests()[0]
+	if req.PresaleType != "stock" || req.ComponentType != "base" {
+		t.Errorf("expected presale and library type to be sent, got %+v", req)
+	}
+}
+
+// TestGetProductDetailsFakeServer tests product lookup against the fake server.
+func TestGetProductDetailsFakeServer(t *testing.T) {
+	_, client := newFakeServerClient(t)
+
+	product, err := client.GetProductDetails(context.Background(), "C5676715")
+	if err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+
+	if product.ComponentModelEn != "MPM3506AGQV-Z" {
+		t.Errorf("unexpected product: %s", product.ComponentModelEn)
+	}
+	if product.GetProductURL() != "https://jlcpcb.com/parts/details/6597989-MPM3506AGQVZ/C5676715" {
+		t.Errorf("unexpected URL: %s", product.GetProductURL())
+	}
+}
+
+// TestGetProductDetailsNotFoundFakeServer tests lookup of an unknown part.
+func TestGetProductDetailsNotFoundFakeServer(t *testing.T) {
+	_, client := newFakeServerClient(t)
+
+	var notFound jlcpcb.ErrProductNotFound
+	if _, err := client.GetProductDetails(context.Background(), "C99999999"); !errors.As(err, &notFound) {
+		t.Fatalf("expected ErrProductNotFound for unknown part, got %v", err)
+	}
+
+	// A keyword match on another part is not the requested part.
+	if _, err := client.GetProductDetails(context.Background(), "0402"); !errors.As(err, &notFound) {
+		t.Fatalf("expected ErrProductNotFound for a code matching other parts, got %v", err)
+	}
+}
+
+// TestKeywordSearchCachingFakeServer tests that cached searches skip the server.
+func TestKeywordSearchCachingFakeServer(t *testing.T) {
+	server, client := newFakeServerClient(t, jlcpcb.WithCache(jlcpcb.NewMemoryCache()))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.KeywordSearch(ctx, jlcpcb.SearchRequest{Keyword: "diode"}); err != nil {
+			t.Fatalf("KeywordSearch failed: %v", err)
+		}
+	}
+
+	if got := len(server.Requests()); got != 1 {
+		t.Errorf("expected 1 server request, got %d", got)
+	}
+}
+
+// TestKeywordSearchRetryFakeServer tests retry on transient server errors.
+func TestKeywordSearchRetryFakeServer(t *testing.T) {
+	server, client := newFakeServerClient(t)
+	server.InjectFault(jlcpcbtest.Fault{Status: http.StatusGatewayTimeout, Times: 2})
+
+	if _, err := client.KeywordSearch(context.Background(), jlcpcb.SearchRequest{Keyword: "led"}); err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+
+	if got := len(server.Requests()); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+// TestKeywordSearchNoRetryFakeServer tests that non-transient errors are not retried.
+func TestKeywordSearchNoRetryFakeServer(t *testing.T) {
+	server, client := newFakeServerClient(t)
+	server.InjectFault(jlcpcbtest.Fault{Status: http.StatusInternalServerError})
+
+	if _, err := client.KeywordSearch(context.Background(), jlcpcb.SearchRequest{Keyword: "led"}); err == nil {
+		t.Fatal("expected error for 500 response")
+	}
+
+	if got := len(server.Requests()); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+}
@@ -169,7 +169,7 @@ func TestGetProductDetailsFieldsIntegration(t *testing.T) {
 		if pb.StartNumber <= 0 {
 			t.Errorf("price break %d has non-positive start number", i)
 		}
-		if pb.ProductPrice < 0 {
+		if pb.ProductPrice.Sign() < 0 {
 			t.Errorf("price break %d has negative price", i)
 		}
 	}
@@ -2,62 +2,211 @@ package jlcpcb
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"sync"
 	"time"
 )
 
+// Limiter controls how fast the client sends requests.
+// Wait blocks until a request may be sent or ctx is done.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// FeedbackLimiter is a Limiter that adjusts itself based on API responses.
+// The client calls Feedback with the HTTP status code of every response.
+type FeedbackLimiter interface {
+	Limiter
+	Feedback(statusCode int)
+}
+
+// Clock provides the current time and timers, so that limiters can be
+// tested without sleeping.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock is a Clock backed by the time package.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+var _ Limiter = (*RateLimiter)(nil)
+
+// RateLimiterConfig contains token bucket configuration.
+type RateLimiterConfig struct {
+	Rate  float64 // Tokens added per second
+	Burst int     // Maximum tokens in the bucket; 0 uses the rate rounded up
+	Clock Clock   // Time source; nil uses the system clock
+}
+
 // RateLimiter implements token bucket rate limiting.
+// Tokens may be reserved ahead of time, leaving the bucket in debt; later
+// callers wait until the debt has been repaid.
 type RateLimiter struct {
 	mu         sync.Mutex
+	clock      Clock
 	rate       float64   // requests per second
 	maxTokens  float64   // maximum tokens in bucket
-	tokens     float64   // current tokens
+	tokens     float64   // current tokens, negative while reservations are outstanding
 	lastUpdate time.Time // last time tokens were updated
 }
 
-// NewRateLimiter creates a new rate limiter.
+// NewRateLimiter creates a new rate limiter with a burst equal to the rate.
 func NewRateLimiter(rps float64) *RateLimiter {
-	if rps <= 0 {
-		rps = 1.0
+	return NewRateLimiterWithConfig(RateLimiterConfig{Rate: rps})
+}
+
+// NewRateLimiterWithConfig creates a rate limiter with a full bucket.
+func NewRateLimiterWithConfig(config RateLimiterConfig) *RateLimiter {
+	if config.Rate <= 0 {
+		config.Rate = 1.0
+	}
+	if config.Burst <= 0 {
+		config.Burst = defaultBurst(config.Rate)
+	}
+	if config.Clock == nil {
+		config.Clock = systemClock{}
 	}
 	return &RateLimiter{
-		rate:       rps,
-		maxTokens:  rps,
-		tokens:     rps,
-		lastUpdate: time.Now(),
+		clock:      config.Clock,
+		rate:       config.Rate,
+		maxTokens:  float64(config.Burst),
+		tokens:     float64(config.Burst),
+		lastUpdate: config.Clock.Now(),
 	}
 }
 
 // Wait blocks until a token is available.
 func (rl *RateLimiter) Wait(ctx context.Context) error {
-	for {
-		rl.mu.Lock()
-		rl.refill()
+	return rl.WaitN(ctx, 1)
+}
+
+// WaitN blocks until n tokens are available. It fails immediately if n
+// exceeds the burst size. If ctx is done first, the tokens are returned.
+func (rl *RateLimiter) WaitN(ctx context.Context, n int) error {
+	if n <= 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-		if rl.tokens >= 1.0 {
-			rl.tokens -= 1.0
-			rl.mu.Unlock()
-			return nil
-		}
-		rl.mu.Unlock()
+	r := rl.reserveN(n)
+	if !r.OK() {
+		return fmt.Errorf("rate limiter: %d tokens exceeds burst of %d", n, rl.Burst())
+	}
+
+	delay := r.Delay()
+	if delay <= 0 {
+		return nil
+	}
+
+	select {
+	case <-rl.clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	}
+}
+
+// TryAcquire takes a token if one is available without waiting.
+func (rl *RateLimiter) TryAcquire() bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.refill()
+	if rl.tokens < 1.0 {
+		return false
+	}
+	rl.tokens -= 1.0
+	return true
+}
 
-		// Calculate wait time
-		waitTime := time.Duration(float64(time.Second) / rl.rate)
-		select {
-		case <-time.After(waitTime):
-			continue
-		case <-ctx.Done():
-			return ctx.Err()
-		}
+// Reserve takes a token now and reports how long the caller must wait
+// before acting on it. Call Cancel on the reservation to give the token back
+// if the caller decides not to act.
+func (rl *RateLimiter) Reserve() *Reservation {
+	return rl.reserveN(1)
+}
+
+// SetRate changes the refill rate. Tokens accrued so far are kept.
+func (rl *RateLimiter) SetRate(rps float64) {
+	if rps <= 0 {
+		return
 	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.refill()
+	rl.rate = rps
+}
+
+// SetBurst changes the bucket size, discarding tokens above it.
+func (rl *RateLimiter) SetBurst(burst int) {
+	if burst <= 0 {
+		return
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.refill()
+	rl.maxTokens = float64(burst)
+	if rl.tokens > rl.maxTokens {
+		rl.tokens = rl.maxTokens
+	}
+}
+
+// Rate returns the refill rate in tokens per second.
+func (rl *RateLimiter) Rate() float64 {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	return rl.rate
+}
+
+// Burst returns the bucket size.
+func (rl *RateLimiter) Burst() int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	return int(rl.maxTokens)
+}
+
+// reserveN takes n tokens, going into debt if necessary.
+func (rl *RateLimiter) reserveN(n int) *Reservation {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if float64(n) > rl.maxTokens {
+		return &Reservation{limiter: rl, tokens: n}
+	}
+
+	rl.refill()
+	now := rl.lastUpdate
+	rl.tokens -= float64(n)
+
+	var delay time.Duration
+	if rl.tokens < 0 {
+		delay = time.Duration(-rl.tokens / rl.rate * float64(time.Second))
+	}
+	return &Reservation{limiter: rl, ok: true, tokens: n, timeToAct: now.Add(delay)}
 }
 
 // refill adds tokens based on time elapsed.
 // Must be called with mu held.
 func (rl *RateLimiter) refill() {
-	now := time.Now()
+	now := rl.clock.Now()
 	elapsed := now.Sub(rl.lastUpdate).Seconds()
-	rl.tokens += elapsed * rl.rate
+	if elapsed > 0 {
+		rl.tokens += elapsed * rl.rate
+	}
 
 	if rl.tokens > rl.maxTokens {
 		rl.tokens = rl.maxTokens
@@ -65,3 +214,68 @@ func (rl *RateLimiter) refill() {
 
 	rl.lastUpdate = now
 }
+
+// Reservation holds tokens taken from a RateLimiter by Reserve.
+type Reservation struct {
+	mu        sync.Mutex
+	limiter   *RateLimiter
+	ok        bool
+	cancelled bool
+	tokens    int
+	timeToAct time.Time
+}
+
+// OK reports whether the reservation could be made. It is false only when
+// more tokens were requested than the bucket can hold.
+func (r *Reservation) OK() bool {
+	return r.ok
+}
+
+// Delay returns how long to wait before acting on the reservation, measured
+// from the limiter's current time. It is zero if the caller may act now and
+// math.MaxInt64 if the reservation is not OK.
+func (r *Reservation) Delay() time.Duration {
+	if !r.ok {
+		return time.Duration(math.MaxInt64)
+	}
+	delay := r.timeToAct.Sub(r.limiter.clock.Now())
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+// Cancel returns the reserved tokens to the limiter if the reservation has
+// not yet become due. Calling Cancel more than once has no further effect.
+func (r *Reservation) Cancel() {
+	if !r.ok {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancelled {
+		return
+	}
+	r.cancelled = true
+
+	rl := r.limiter
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if !rl.clock.Now().Before(r.timeToAct) {
+		return
+	}
+	rl.refill()
+	rl.tokens += float64(r.tokens)
+	if rl.tokens > rl.maxTokens {
+		rl.tokens = rl.maxTokens
+	}
+}
+
+// defaultBurst returns the burst used when none is configured: the rate
+// rounded up, and at least one token so that slow rates still admit requests.
+func defaultBurst(rps float64) int {
+	return max(int(math.Ceil(rps)), 1)
+}
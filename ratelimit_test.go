@@ -2,6 +2,7 @@ package jlcpcb
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 )
@@ -180,3 +181,259 @@ func TestRateLimiterWaitConcurrent(t *testing.T) {
 		}
 	}
 }
+
+// fakeClock is a manually advanced Clock for deterministic limiter tests.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []fakeTimer
+}
+
+// fakeTimer is a pending After call on a fakeClock.
+type fakeTimer struct {
+	deadline time.Time
+	ch       chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (fc *fakeClock) Now() time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	return fc.now
+}
+
+func (fc *fakeClock) After(d time.Duration) <-chan time.Time {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- fc.now
+		return ch
+	}
+	fc.waiters = append(fc.waiters, fakeTimer{deadline: fc.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock forward, firing any timers that became due.
+func (fc *fakeClock) Advance(d time.Duration) {
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	fc.now = fc.now.Add(d)
+	pending := fc.waiters[:0]
+	for _, w := range fc.waiters {
+		if w.deadline.After(fc.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- fc.now
+	}
+	fc.waiters = pending
+}
+
+// BlockUntil waits until n timers are pending.
+func (fc *fakeClock) BlockUntil(t *testing.T, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		fc.mu.Lock()
+		pending := len(fc.waiters)
+		fc.mu.Unlock()
+		if pending >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d pending timers", n)
+}
+
+// TestRateLimiterClock tests that Wait refills tokens from the injected clock.
+func TestRateLimiterClock(t *testing.T) {
+	clock := newFakeClock()
+	rl := NewRateLimiterWithConfig(RateLimiterConfig{Rate: 1.0, Clock: clock})
+
+	if err := rl.Wait(context.Background()); err != nil {
+		t.Fatalf("initial wait failed: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- rl.Wait(context.Background()) }()
+
+	clock.BlockUntil(t, 1)
+	select {
+	case <-done:
+		t.Fatal("expected Wait to block until the clock advances")
+	default:
+	}
+
+	clock.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+}
+
+// TestRateLimiterFractionalRate tests that rates below one request per second
+// still admit requests.
+func TestRateLimiterFractionalRate(t *testing.T) {
+	clock := newFakeClock()
+	rl := NewRateLimiterWithConfig(RateLimiterConfig{Rate: 0.5, Clock: clock})
+
+	if err := rl.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+
+	if rl.TryAcquire() {
+		t.Error("expected no token immediately after the first")
+	}
+	clock.Advance(2 * time.Second)
+	if !rl.TryAcquire() {
+		t.Error("expected a token after 2s at 0.5 rps")
+	}
+}
+
+// TestNewRateLimiterWithConfig tests a burst independent of the rate.
+func TestNewRateLimiterWithConfig(t *testing.T) {
+	clock := newFakeClock()
+	rl := NewRateLimiterWithConfig(RateLimiterConfig{Rate: 2, Burst: 5, Clock: clock})
+
+	if rl.Rate() != 2 || rl.Burst() != 5 {
+		t.Fatalf("expected rate 2 and burst 5, got %f and %d", rl.Rate(), rl.Burst())
+	}
+
+	for i := 0; i < 5; i++ {
+		if !rl.TryAcquire() {
+			t.Fatalf("expected token %d of the burst", i+1)
+		}
+	}
+	if rl.TryAcquire() {
+		t.Error("expected bucket to be empty after the burst")
+	}
+
+	clock.Advance(500 * time.Millisecond)
+	if !rl.TryAcquire() {
+		t.Error("expected a token after 500ms at 2 rps")
+	}
+}
+
+// TestRateLimiterReserve tests exact reservation delays.
+func TestRateLimiterReserve(t *testing.T) {
+	clock := newFakeClock()
+	rl := NewRateLimiterWithConfig(RateLimiterConfig{Rate: 4, Burst: 1, Clock: clock})
+
+	want := []time.Duration{0, 250 * time.Millisecond, 500 * time.Millisecond}
+	for i, w := range want {
+		r := rl.Reserve()
+		if !r.OK() {
+			t.Fatalf("reservation %d not OK", i)
+		}
+		if r.Delay() != w {
+			t.Errorf("reservation %d: expected delay %v, got %v", i, w, r.Delay())
+		}
+	}
+
+	clock.Advance(100 * time.Millisecond)
+	r := rl.Reserve()
+	if r.Delay() != 650*time.Millisecond {
+		t.Errorf("expected delay 650ms, got %v", r.Delay())
+	}
+}
+
+// TestReservationCancel tests that cancelling returns tokens to the bucket.
+func TestReservationCancel(t *testing.T) {
+	clock := newFakeClock()
+	rl := NewRateLimiterWithConfig(RateLimiterConfig{Rate: 1, Burst: 1, Clock: clock})
+
+	rl.Reserve()
+	r := rl.Reserve()
+	if r.Delay() != time.Second {
+		t.Fatalf("expected delay 1s, got %v", r.Delay())
+	}
+
+	r.Cancel()
+	r.Cancel()
+	if next := rl.Reserve(); next.Delay() != time.Second {
+		t.Errorf("expected cancelled token to be reusable, got delay %v", next.Delay())
+	}
+}
+
+// TestRateLimiterWaitNExactDelay tests that WaitN sleeps exactly once for the computed delay.
+func TestRateLimiterWaitNExactDelay(t *testing.T) {
+	clock := newFakeClock()
+	rl := NewRateLimiterWithConfig(RateLimiterConfig{Rate: 2, Burst: 3, Clock: clock})
+	ctx := context.Background()
+
+	if err := rl.WaitN(ctx, 3); err != nil {
+		t.Fatalf("WaitN failed: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- rl.WaitN(ctx, 2) }()
+
+	clock.BlockUntil(t, 1)
+	clock.mu.Lock()
+	deadline := clock.waiters[0].deadline
+	clock.mu.Unlock()
+	if got := deadline.Sub(clock.Now()); got != time.Second {
+		t.Errorf("expected a single 1s timer, got %v", got)
+	}
+
+	clock.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Fatalf("WaitN failed: %v", err)
+	}
+}
+
+// TestRateLimiterWaitNExceedsBurst tests that WaitN rejects requests larger than the burst.
+func TestRateLimiterWaitNExceedsBurst(t *testing.T) {
+	rl := NewRateLimiterWithConfig(RateLimiterConfig{Rate: 10, Burst: 2, Clock: newFakeClock()})
+
+	if err := rl.WaitN(context.Background(), 3); err == nil {
+		t.Error("expected error for n above burst")
+	}
+	if r := rl.Reserve(); !r.OK() || r.Delay() != 0 {
+		t.Error("expected failed WaitN not to consume tokens")
+	}
+}
+
+// TestRateLimiterWaitCancelReturnsTokens tests that an abandoned wait frees its reservation.
+func TestRateLimiterWaitCancelReturnsTokens(t *testing.T) {
+	clock := newFakeClock()
+	rl := NewRateLimiterWithConfig(RateLimiterConfig{Rate: 1, Burst: 1, Clock: clock})
+
+	rl.Reserve()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- rl.Wait(ctx) }()
+
+	clock.BlockUntil(t, 1)
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	if r := rl.Reserve(); r.Delay() != time.Second {
+		t.Errorf("expected delay 1s after cancelled wait, got %v", r.Delay())
+	}
+}
+
+// TestRateLimiterSetRate tests changing the rate at runtime.
+func TestRateLimiterSetRate(t *testing.T) {
+	clock := newFakeClock()
+	rl := NewRateLimiterWithConfig(RateLimiterConfig{Rate: 1, Burst: 1, Clock: clock})
+
+	rl.Reserve()
+	rl.SetRate(10)
+	if r := rl.Reserve(); r.Delay() != 100*time.Millisecond {
+		t.Errorf("expected delay 100ms at 10 rps, got %v", r.Delay())
+	}
+
+	rl.SetRate(0)
+	if rl.Rate() != 10 {
+		t.Errorf("expected non-positive rate to be ignored, got %f", rl.Rate())
+	}
+}
@@ -0,0 +1,212 @@
+package jlcpcb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const defaultSharedLimiterKey = "jlcpcb.com"
+
+// LimiterStore holds rate limiter state shared by several clients, possibly
+// in different processes. Take must be atomic with respect to every other
+// Take on the same key.
+//
+// Stores use the generic cell rate algorithm (GCRA): the only state kept per
+// key is a theoretical arrival time, so a key-value store such as Redis can
+// implement Take with a single script. See GCRA for the update rule.
+type LimiterStore interface {
+	// Take reserves one request for key, allowing burst requests at once and
+	// one more every interval after that. It returns how long the caller must
+	// wait, measured from now, before sending the request.
+	Take(ctx context.Context, key string, now time.Time, interval time.Duration, burst int) (time.Duration, error)
+}
+
+// GCRA applies one request to a stored theoretical arrival time and returns
+// the new arrival time to store and how long the caller must wait. A zero tat
+// means no request has been made yet.
+func GCRA(tat, now time.Time, interval time.Duration, burst int) (time.Time, time.Duration) {
+	if tat.Before(now) {
+		tat = now
+	}
+	next := tat.Add(interval)
+	allowAt := next.Add(-time.Duration(max(burst, 1)) * interval)
+
+	wait := allowAt.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	return next, wait
+}
+
+// MemoryLimiterStore is a LimiterStore for limiters within a single process.
+type MemoryLimiterStore struct {
+	mu   sync.Mutex
+	tats map[string]time.Time
+}
+
+// NewMemoryLimiterStore creates a new in-memory limiter store.
+func NewMemoryLimiterStore() *MemoryLimiterStore {
+	return &MemoryLimiterStore{
+		tats: make(map[string]time.Time),
+	}
+}
+
+// Take reserves one request for key.
+func (ms *MemoryLimiterStore) Take(ctx context.Context, key string, now time.Time, interval time.Duration, burst int) (time.Duration, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	next, wait := GCRA(ms.tats[key], now, interval, burst)
+	ms.tats[key] = next
+	return wait, nil
+}
+
+// FileLimiterStore is a LimiterStore shared by processes on one machine
+// through a state file guarded by a file lock. Every process that should
+// share a budget must use the same path.
+type FileLimiterStore struct {
+	path string
+}
+
+// NewFileLimiterStore creates a limiter store backed by the file at path.
+// The file and its directory are created on first use.
+func NewFileLimiterStore(path string) *FileLimiterStore {
+	return &FileLimiterStore{path: path}
+}
+
+// Take reserves one request for key, holding the file lock while the state
+// is read and rewritten.
+func (fls *FileLimiterStore) Take(ctx context.Context, key string, now time.Time, interval time.Duration, burst int) (time.Duration, error) {
+	if err := os.MkdirAll(filepath.Dir(fls.path), 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create limiter directory: %w", err)
+	}
+
+	f, err := os.OpenFile(fls.path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open limiter file: %w", err)
+	}
+	defer f.Close()
+
+	unlock, err := lockFile(ctx, f)
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock limiter file: %w", err)
+	}
+	defer unlock()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read limiter file: %w", err)
+	}
+
+	// State maps keys to theoretical arrival times in Unix nanoseconds.
+	state := make(map[string]int64)
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &state); err != nil {
+			return 0, fmt.Errorf("failed to parse limiter file: %w", err)
+		}
+	}
+
+	var tat time.Time
+	if ns, ok := state[key]; ok {
+		tat = time.Unix(0, ns)
+	}
+	next, wait := GCRA(tat, now, interval, burst)
+	state[key] = next.UnixNano()
+
+	// Drop keys whose budget has fully recovered.
+	for k, ns := range state {
+		if k != key && ns < now.UnixNano() {
+			delete(state, k)
+		}
+	}
+
+	data, err = json.Marshal(state)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode limiter state: %w", err)
+	}
+	if err := f.Truncate(0); err != nil {
+		return 0, fmt.Errorf("failed to write limiter file: %w", err)
+	}
+	if _, err := f.WriteAt(data, 0); err != nil {
+		return 0, fmt.Errorf("failed to write limiter file: %w", err)
+	}
+
+	return wait, nil
+}
+
+// SharedLimiterConfig contains shared rate limiter configuration.
+type SharedLimiterConfig struct {
+	Store LimiterStore // Shared state; required
+	Key   string       // Budget name; clients with the same key share a budget
+	Rate  float64      // Requests per second across all clients
+	Burst int          // Requests allowed at once; 0 uses the rate rounded up
+	Clock Clock        // Time source; nil uses the system clock
+}
+
+// SharedLimiter is a Limiter whose budget is shared through a LimiterStore,
+// so that several processes together stay within one rate.
+type SharedLimiter struct {
+	store    LimiterStore
+	key      string
+	interval time.Duration
+	burst    int
+	clock    Clock
+}
+
+var _ Limiter = (*SharedLimiter)(nil)
+
+// NewSharedLimiter creates a shared rate limiter. It panics if config.Store
+// is nil.
+func NewSharedLimiter(config SharedLimiterConfig) *SharedLimiter {
+	if config.Store == nil {
+		panic("jlcpcb: SharedLimiterConfig.Store is nil")
+	}
+	if config.Key == "" {
+		config.Key = defaultSharedLimiterKey
+	}
+	if config.Rate <= 0 {
+		config.Rate = defaultRateLimit
+	}
+	if config.Burst <= 0 {
+		config.Burst = defaultBurst(config.Rate)
+	}
+	if config.Clock == nil {
+		config.Clock = systemClock{}
+	}
+	return &SharedLimiter{
+		store:    config.Store,
+		key:      config.Key,
+		interval: time.Duration(float64(time.Second) / config.Rate),
+		burst:    config.Burst,
+		clock:    config.Clock,
+	}
+}
+
+// Wait reserves a request slot in the shared budget and blocks until it is
+// due. A slot abandoned because ctx is done is not returned to the budget.
+func (sl *SharedLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	wait, err := sl.store.Take(ctx, sl.key, sl.clock.Now(), sl.interval, sl.burst)
+	if err != nil {
+		return fmt.Errorf("shared limiter: %w", err)
+	}
+	if wait <= 0 {
+		return nil
+	}
+
+	select {
+	case <-sl.clock.After(wait):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,167 @@
+package jlcpcb
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestGCRA tests the arrival time update rule.
+func TestGCRA(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	interval := 100 * time.Millisecond
+
+	var tat time.Time
+	var waits []time.Duration
+	for i := 0; i < 4; i++ {
+		var wait time.Duration
+		tat, wait = GCRA(tat, now, interval, 2)
+		waits = append(waits, wait)
+	}
+
+	want := []time.Duration{0, 0, 100 * time.Millisecond, 200 * time.Millisecond}
+	for i := range want {
+		if waits[i] != want[i] {
+			t.Errorf("request %d: expected wait %v, got %v", i, want[i], waits[i])
+		}
+	}
+
+	// After the budget recovers the next request is free again.
+	if _, wait := GCRA(tat, now.Add(time.Second), interval, 2); wait != 0 {
+		t.Errorf("expected no wait after recovery, got %v", wait)
+	}
+}
+
+// TestMemoryLimiterStoreKeys tests that keys have independent budgets.
+func TestMemoryLimiterStoreKeys(t *testing.T) {
+	store := NewMemoryLimiterStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	for _, key := range []string{"a", "b"} {
+		if wait, _ := store.Take(ctx, key, now, time.Second, 1); wait != 0 {
+			t.Errorf("key %s: expected no wait, got %v", key, wait)
+		}
+	}
+	if wait, _ := store.Take(ctx, "a", now, time.Second, 1); wait != time.Second {
+		t.Errorf("expected wait 1s, got %v", wait)
+	}
+}
+
+// TestFileLimiterStoreShared tests that stores on the same file share a budget.
+func TestFileLimiterStoreShared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limits", "jlcpcb.json")
+	ctx := context.Background()
+	now := time.Now()
+
+	// Separate store values open the file independently, like separate processes.
+	const n = 20
+	waits := make([]time.Duration, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			wait, err := NewFileLimiterStore(path).Take(ctx, "api", now, 50*time.Millisecond, 5)
+			if err != nil {
+				t.Errorf("Take failed: %v", err)
+			}
+			waits[i] = wait
+		}(i)
+	}
+	wg.Wait()
+
+	sort.Slice(waits, func(i, j int) bool { return waits[i] < waits[j] })
+	for i, wait := range waits {
+		want := time.Duration(max(i-4, 0)) * 50 * time.Millisecond
+		if wait != want {
+			t.Errorf("request %d: expected wait %v, got %v", i, want, wait)
+		}
+	}
+}
+
+// TestFileLimiterStoreContended tests that Take gives up on a held lock when ctx is done.
+func TestFileLimiterStoreContended(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limits.json")
+	store := NewFileLimiterStore(path)
+	if _, err := store.Take(context.Background(), "api", time.Now(), time.Second, 1); err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatalf("failed to open limiter file: %v", err)
+	}
+	defer f.Close()
+	unlock, err := lockFile(context.Background(), f)
+	if err != nil {
+		t.Fatalf("failed to lock limiter file: %v", err)
+	}
+	defer unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := store.Take(ctx, "api", time.Now(), time.Second, 1); err == nil {
+		t.Error("expected error while the lock is held")
+	}
+}
+
+// TestSharedLimiterWait tests that limiters sharing a store split one budget.
+func TestSharedLimiterWait(t *testing.T) {
+	clock := newFakeClock()
+	store := NewMemoryLimiterStore()
+	config := SharedLimiterConfig{Store: store, Rate: 2, Burst: 1, Clock: clock}
+	a, b := NewSharedLimiter(config), NewSharedLimiter(config)
+	ctx := context.Background()
+
+	if err := a.Wait(ctx); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- b.Wait(ctx) }()
+
+	clock.BlockUntil(t, 1)
+	clock.Advance(500 * time.Millisecond)
+	if err := <-done; err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+}
+
+// TestSharedLimiterContext tests cancellation while waiting for a slot.
+func TestSharedLimiterContext(t *testing.T) {
+	clock := newFakeClock()
+	sl := NewSharedLimiter(SharedLimiterConfig{Store: NewMemoryLimiterStore(), Rate: 1, Clock: clock})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if err := sl.Wait(ctx); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- sl.Wait(ctx) }()
+
+	clock.BlockUntil(t, 1)
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// TestClientSharedLimiter tests using a shared limiter through the client.
+func TestClientSharedLimiter(t *testing.T) {
+	server := newSearchServer(t, Product{ComponentCode: "C1"})
+	store := NewFileLimiterStore(filepath.Join(t.TempDir(), "limits.json"))
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithLimiter(NewSharedLimiter(SharedLimiterConfig{Store: store, Rate: 100})),
+	)
+
+	if _, err := client.KeywordSearch(context.Background(), SearchRequest{Keyword: "C1"}); err != nil {
+		t.Fatalf("KeywordSearch failed: %v", err)
+	}
+}
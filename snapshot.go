@@ -0,0 +1,261 @@
+package jlcpcb
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Snapshot is a timestamped record of a product's stock and prices.
+type Snapshot struct {
+	ComponentCode string       `json:"componentCode"`      // JLCPCB part code
+	Timestamp     time.Time    `json:"timestamp"`          // Time the product was fetched
+	StockCount    int          `json:"stockCount"`         // Stock quantity at that time
+	Prices        []PriceBreak `json:"prices"`             // Price breaks at that time
+	Currency      string       `json:"currency,omitempty"` // Currency of the prices
+}
+
+// NewSnapshot creates a snapshot of the product's current stock and prices.
+func NewSnapshot(p *Product, at time.Time) Snapshot {
+	prices := make([]PriceBreak, len(p.ComponentPrices))
+	copy(prices, p.ComponentPrices)
+	return Snapshot{
+		ComponentCode: p.ComponentCode,
+		Timestamp:     at,
+		StockCount:    p.StockCount,
+		Prices:        prices,
+		Currency:      p.Currency,
+	}
+}
+
+// SnapshotStore interface defines storage for product snapshots.
+// A zero from or to time leaves that end of the History window unbounded.
+type SnapshotStore interface {
+	Record(snapshots []Snapshot) error
+	History(componentCode string, from, to time.Time) ([]Snapshot, error)
+}
+
+// MemorySnapshotStore is an in-memory snapshot store implementation.
+type MemorySnapshotStore struct {
+	mu    sync.RWMutex
+	items map[string][]Snapshot
+}
+
+// NewMemorySnapshotStore creates a new in-memory snapshot store.
+func NewMemorySnapshotStore() *MemorySnapshotStore {
+	return &MemorySnapshotStore{
+		items: make(map[string][]Snapshot),
+	}
+}
+
+// Record stores the given snapshots.
+func (ms *MemorySnapshotStore) Record(snapshots []Snapshot) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for _, s := range snapshots {
+		ms.items[s.ComponentCode] = append(ms.items[s.ComponentCode], s)
+	}
+	return nil
+}
+
+// History returns the snapshots of a product within the window, oldest first.
+func (ms *MemorySnapshotStore) History(componentCode string, from, to time.Time) ([]Snapshot, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var result []Snapshot
+	for _, s := range ms.items[componentCode] {
+		if inWindow(s.Timestamp, from, to) {
+			result = append(result, s)
+		}
+	}
+	sortSnapshots(result)
+	return result, nil
+}
+
+// FileSnapshotStore is a snapshot store that appends snapshots to a local
+// file, one JSON object per line.
+type FileSnapshotStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileSnapshotStore creates a snapshot store backed by the file at path.
+// The file is created on the first Record call.
+func NewFileSnapshotStore(path string) *FileSnapshotStore {
+	return &FileSnapshotStore{path: path}
+}
+
+// Record appends the given snapshots to the file.
+func (fss *FileSnapshotStore) Record(snapshots []Snapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	fss.mu.Lock()
+	defer fss.mu.Unlock()
+
+	f, err := os.OpenFile(fss.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot file: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, s := range snapshots {
+		if err := enc.Encode(s); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to write snapshot: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return f.Close()
+}
+
+// History returns the snapshots of a product within the window, oldest first.
+func (fss *FileSnapshotStore) History(componentCode string, from, to time.Time) ([]Snapshot, error) {
+	fss.mu.Lock()
+	defer fss.mu.Unlock()
+
+	f, err := os.Open(fss.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open snapshot file: %w", err)
+	}
+	defer f.Close()
+
+	var result []Snapshot
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var s Snapshot
+		if err := json.Unmarshal(line, &s); err != nil {
+			return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+		}
+		if s.ComponentCode == componentCode && inWindow(s.Timestamp, from, to) {
+			tagPrices(s.Prices, s.Currency)
+			result = append(result, s)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot file: %w", err)
+	}
+
+	sortSnapshots(result)
+	return result, nil
+}
+
+// PriceRange summarizes the unit prices observed for a product over a window.
+type PriceRange struct {
+	Quantity int       // Order quantity the prices apply to
+	Min      Money     // Lowest observed unit price
+	MinAt    time.Time // When the lowest price was first observed
+	Max      Money     // Highest observed unit price
+	MaxAt    time.Time // When the highest price was first observed
+	Samples  int       // Number of snapshots with a price for the quantity
+	Currency string    // Currency of the prices compared
+	Skipped  int       // Snapshots skipped because they are in another currency
+}
+
+// MinMaxPrice returns the lowest and highest unit price recorded for a
+// product at the given order quantity between from and to. Prices are only
+// comparable within one currency, so snapshots in a currency other than
+// that of the most recent priced snapshot are skipped and counted.
+func MinMaxPrice(store SnapshotStore, componentCode string, quantity int, from, to time.Time) (*PriceRange, error) {
+	history, err := store.History(componentCode, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	pr := &PriceRange{Quantity: quantity}
+	for i := len(history) - 1; i >= 0; i-- {
+		if price, ok := priceForQuantity(history[i].Prices, quantity); ok {
+			pr.Currency = snapshotCurrency(&history[i], price)
+			break
+		}
+	}
+
+	for _, s := range history {
+		price, ok := priceForQuantity(s.Prices, quantity)
+		if !ok {
+			continue
+		}
+		if !strings.EqualFold(snapshotCurrency(&s, price), pr.Currency) {
+			pr.Skipped++
+			continue
+		}
+		if pr.Samples == 0 || price.Cmp(pr.Min) < 0 {
+			pr.Min, pr.MinAt = price, s.Timestamp
+		}
+		if pr.Samples == 0 || price.Cmp(pr.Max) > 0 {
+			pr.Max, pr.MaxAt = price, s.Timestamp
+		}
+		pr.Samples++
+	}
+
+	if pr.Samples == 0 {
+		return nil, fmt.Errorf("no price history for %s", componentCode)
+	}
+	return pr, nil
+}
+
+// snapshotCurrency returns the currency of a snapshot's price: the
+// snapshot's Currency, or the price's own if the snapshot has none.
+func snapshotCurrency(s *Snapshot, price Money) string {
+	if s.Currency != "" {
+		return s.Currency
+	}
+	return price.Currency
+}
+
+// recordSnapshots stores snapshots of freshly fetched products.
+// Failures are ignored so that history recording never breaks a lookup.
+func (c *Client) recordSnapshots(products []Product) {
+	if c.snapshots == nil || len(products) == 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	snapshots := make([]Snapshot, 0, len(products))
+	for i := range products {
+		if products[i].ComponentCode == "" {
+			continue
+		}
+		snapshots = append(snapshots, NewSnapshot(&products[i], now))
+	}
+	_ = c.snapshots.Record(snapshots)
+}
+
+// inWindow reports whether t lies within [from, to], treating zero bounds as open.
+func inWindow(t, from, to time.Time) bool {
+	if !from.IsZero() && t.Before(from) {
+		return false
+	}
+	if !to.IsZero() && t.After(to) {
+		return false
+	}
+	return true
+}
+
+// sortSnapshots orders snapshots oldest first.
+func sortSnapshots(snapshots []Snapshot) {
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Timestamp.Before(snapshots[j].Timestamp)
+	})
+}
@@ -0,0 +1,222 @@
+package jlcpcb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestNewSnapshot tests that a snapshot copies stock and prices.
+func TestNewSnapshot(t *testing.T) {
+	product := &Product{
+		ComponentCode:   "C25744",
+		StockCount:      1000,
+		ComponentPrices: []PriceBreak{{StartNumber: 1, EndNumber: -1, ProductPrice: MoneyFromFloat(0.002, "")}},
+	}
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s := NewSnapshot(product, at)
+	product.ComponentPrices[0].ProductPrice = MoneyFromFloat(1, "")
+
+	if s.ComponentCode != "C25744" || s.StockCount != 1000 || !s.Timestamp.Equal(at) {
+		t.Errorf("unexpected snapshot: %+v", s)
+	}
+	if s.Prices[0].ProductPrice != MoneyFromFloat(0.002, "") {
+		t.Errorf("expected snapshot prices to be copied, got %v", s.Prices[0].ProductPrice)
+	}
+}
+
+// TestMemorySnapshotStoreHistory tests window filtering and ordering.
+func TestMemorySnapshotStoreHistory(t *testing.T) {
+	testSnapshotStoreHistory(t, NewMemorySnapshotStore())
+}
+
+// TestFileSnapshotStoreHistory tests window filtering and ordering for the file store.
+func TestFileSnapshotStoreHistory(t *testing.T) {
+	testSnapshotStoreHistory(t, NewFileSnapshotStore(filepath.Join(t.TempDir(), "history.jsonl")))
+}
+
+// TestFileSnapshotStoreMissingFile tests that a missing file has no history.
+func TestFileSnapshotStoreMissingFile(t *testing.T) {
+	store := NewFileSnapshotStore(filepath.Join(t.TempDir(), "missing.jsonl"))
+
+	history, err := store.History("C1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(history) != 0 {
+		t.Errorf("expected empty history, got %d", len(history))
+	}
+}
+
+// TestFileSnapshotStorePersists tests that snapshots survive reopening the store.
+func TestFileSnapshotStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := NewFileSnapshotStore(path).Record([]Snapshot{{ComponentCode: "C1", Timestamp: at, StockCount: 7}}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	history, err := NewFileSnapshotStore(path).History("C1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(history) != 1 || history[0].StockCount != 7 || !history[0].Timestamp.Equal(at) {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
+
+// TestMinMaxPrice tests min/max price calculation over a window.
+func TestMinMaxPrice(t *testing.T) {
+	store := NewMemorySnapshotStore()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	prices := []float64{0.05, 0.03, 0.08, 0.01}
+
+	var snapshots []Snapshot
+	for i, p := range prices {
+		snapshots = append(snapshots, Snapshot{
+			ComponentCode: "C1",
+			Timestamp:     base.AddDate(0, 0, i),
+			Prices: []PriceBreak{
+				{StartNumber: 1, EndNumber: 99, ProductPrice: MoneyFromFloat(p*2, "")},
+				{StartNumber: 100, EndNumber: -1, ProductPrice: MoneyFromFloat(p, "")},
+			},
+		})
+	}
+	if err := store.Record(snapshots); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	pr, err := MinMaxPrice(store, "C1", 100, base, base.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("MinMaxPrice failed: %v", err)
+	}
+
+	if pr.Samples != 3 {
+		t.Errorf("expected 3 samples, got %d", pr.Samples)
+	}
+	if pr.Min != MoneyFromFloat(0.03, "") || !pr.MinAt.Equal(base.AddDate(0, 0, 1)) {
+		t.Errorf("unexpected min %v at %v", pr.Min, pr.MinAt)
+	}
+	if pr.Max != MoneyFromFloat(0.08, "") || !pr.MaxAt.Equal(base.AddDate(0, 0, 2)) {
+		t.Errorf("unexpected max %v at %v", pr.Max, pr.MaxAt)
+	}
+
+	pr, err = MinMaxPrice(store, "C1", 10, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("MinMaxPrice failed: %v", err)
+	}
+	if pr.Min != MoneyFromFloat(0.02, "") || pr.Max != MoneyFromFloat(0.16, "") {
+		t.Errorf("expected low-quantity tier prices, got min %v max %v", pr.Min, pr.Max)
+	}
+}
+
+// TestMinMaxPriceCurrencies tests that snapshots in another currency are
+// skipped rather than compared.
+func TestMinMaxPriceCurrencies(t *testing.T) {
+	store := NewMemorySnapshotStore()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := []struct {
+		price    float64
+		currency string
+	}{
+		{0.01, "CNY"},
+		{0.05, "USD"},
+		{0.90, "CNY"},
+		{0.03, "USD"},
+	}
+
+	var snapshots []Snapshot
+	for i, e := range entries {
+		snapshots = append(snapshots, Snapshot{
+			ComponentCode: "C1",
+			Timestamp:     base.AddDate(0, 0, i),
+			Currency:      e.currency,
+			Prices:        []PriceBreak{{StartNumber: 1, EndNumber: -1, ProductPrice: MoneyFromFloat(e.price, e.currency)}},
+		})
+	}
+	if err := store.Record(snapshots); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	pr, err := MinMaxPrice(store, "C1", 1, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("MinMaxPrice failed: %v", err)
+	}
+	if pr.Currency != "USD" || pr.Samples != 2 || pr.Skipped != 2 {
+		t.Errorf("expected 2 USD samples and 2 skipped, got %+v", pr)
+	}
+	if pr.Min.Decimal() != "0.03" || pr.Max.Decimal() != "0.05" {
+		t.Errorf("expected USD prices only, got min %v max %v", pr.Min, pr.Max)
+	}
+}
+
+// TestMinMaxPriceNoHistory tests that a product without history returns an error.
+func TestMinMaxPriceNoHistory(t *testing.T) {
+	_, err := MinMaxPrice(NewMemorySnapshotStore(), "C1", 1, time.Time{}, time.Time{})
+	if err == nil {
+		t.Fatal("expected error for product without history")
+	}
+}
+
+// TestClientRecordsSnapshots tests that fetched products are recorded.
+func TestClientRecordsSnapshots(t *testing.T) {
+	server := newSearchServer(t, Product{
+		ComponentCode:   "C25744",
+		StockCount:      500,
+		ComponentPrices: []PriceBreak{{StartNumber: 1, EndNumber: -1, ProductPrice: MoneyFromFloat(0.001, "")}},
+	})
+	store := NewMemorySnapshotStore()
+	client := NewClient(WithBaseURL(server.URL), WithSnapshotStore(store))
+
+	if _, err := client.GetProductDetails(context.Background(), "C25744"); err != nil {
+		t.Fatalf("GetProductDetails failed: %v", err)
+	}
+
+	history, err := store.History("C25744", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(history) != 1 || history[0].StockCount != 500 {
+		t.Errorf("unexpected history: %+v", history)
+	}
+}
+
+// testSnapshotStoreHistory exercises a SnapshotStore implementation.
+func testSnapshotStoreHistory(t *testing.T, store SnapshotStore) {
+	t.Helper()
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := store.Record([]Snapshot{
+		{ComponentCode: "C1", Timestamp: base.Add(2 * time.Hour), StockCount: 3},
+		{ComponentCode: "C1", Timestamp: base, StockCount: 1},
+		{ComponentCode: "C2", Timestamp: base, StockCount: 100},
+		{ComponentCode: "C1", Timestamp: base.Add(time.Hour), StockCount: 2},
+	})
+	if err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	history, err := store.History("C1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("expected 3 snapshots, got %d", len(history))
+	}
+	for i, s := range history {
+		if s.StockCount != i+1 {
+			t.Errorf("expected snapshots ordered by time, got stock %d at index %d", s.StockCount, i)
+		}
+	}
+
+	history, err = store.History("C1", base.Add(30*time.Minute), base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("History failed: %v", err)
+	}
+	if len(history) != 1 || history[0].StockCount != 2 {
+		t.Errorf("unexpected windowed history: %+v", history)
+	}
+}
@@ -0,0 +1,183 @@
+package jlcpcb
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SourcingMode is how JLCPCB supplies a part for assembly. The values are
+// those accepted by SearchRequest.PresaleType.
+type SourcingMode string
+
+// Sourcing modes.
+const (
+	SourcingStock    SourcingMode = "stock" // From JLCPCB's own stock
+	SourcingGlobal   SourcingMode = "buy"   // Bought in from global suppliers for the order
+	SourcingPreorder SourcingMode = "post"  // Pre-ordered into private stock ahead of the order
+)
+
+// Sourcing is the typed sourcing information of a product.
+type Sourcing struct {
+	Mode         SourcingMode // Preferred way to get the part; empty if unavailable
+	InStock      int          // Parts in JLCPCB's stock
+	Global       bool         // Available through global sourcing
+	LeadTimeDays int          // Global sourcing lead time in days; 0 if unknown
+	BuyPrices    []PriceBreak // Global sourcing price breaks
+}
+
+// Sourcing returns how the product can be sourced. Parts in stock are
+// sourced from stock; other parts JLCPCB can buy in use global sourcing.
+// Pre-order availability is not part of the product data; use Preorderable.
+func (p *Product) Sourcing() Sourcing {
+	s := Sourcing{
+		InStock:   p.StockCount,
+		Global:    p.IsBuyable(),
+		BuyPrices: p.BuyComponentPrices,
+	}
+	s.LeadTimeDays, _ = p.LeadTimeDays()
+
+	switch {
+	case s.InStock > 0:
+		s.Mode = SourcingStock
+	case s.Global:
+		s.Mode = SourcingGlobal
+	}
+	return s
+}
+
+// IsBuyable reports whether JLCPCB can buy the part in through global
+// sourcing, as flagged by IsBuyComponent.
+func (p *Product) IsBuyable() bool {
+	v, err := strconv.ParseBool(strings.TrimSpace(p.IsBuyComponent))
+	return err == nil && v
+}
+
+// LeadTimeDays parses LeadTime into days. Plain numbers are days; "weeks"
+// multiplies by seven, and a range such as "3-5 weeks" gives its upper
+// bound. ok is false if LeadTime is empty or not understood.
+func (p *Product) LeadTimeDays() (days int, ok bool) {
+	text := strings.ToLower(strings.TrimSpace(string(p.LeadTime)))
+	if text == "" {
+		return 0, false
+	}
+
+	// Take the last number, the upper bound of any range.
+	numbers := strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsDigit(r) })
+	if len(numbers) == 0 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(numbers[len(numbers)-1])
+	if err != nil {
+		return 0, false
+	}
+	if strings.Contains(text, "week") {
+		n *= 7
+	}
+	return n, true
+}
+
+// Preorderable reports whether a part is offered for pre-order, by
+// searching for its code with PresaleType "post".
+func Preorderable(ctx context.Context, api PartsAPI, partCode string) (bool, error) {
+	partCode = strings.TrimSpace(partCode)
+	if partCode == "" {
+		return false, fmt.Errorf("part code is required")
+	}
+
+	resp, err := api.KeywordSearch(ctx, SearchRequest{
+		Keyword:     partCode,
+		PageSize:    1,
+		PresaleType: string(SourcingPreorder),
+	})
+	if err != nil {
+		return false, err
+	}
+	return len(resp.Products) > 0 && strings.EqualFold(resp.Products[0].ComponentCode, partCode), nil
+}
+
+// BuyUnitPrice returns the global sourcing unit price that applies when
+// ordering quantity parts.
+func (p *Product) BuyUnitPrice(quantity int) (Money, bool) {
+	return priceForQuantity(p.BuyComponentPrices, quantity)
+}
+
+// SourcingQuote is the cost of getting parts one way.
+type SourcingQuote struct {
+	Mode         SourcingMode
+	Available    bool  // Whether the order quantity can be supplied this way
+	Quantity     int   // Parts ordered, including attrition and MOQ
+	UnitPrice    Money // Zero if unpriced
+	Total        Money // Zero if unpriced
+	LeadTimeDays int   // Days before the parts arrive; 0 for stock or if unknown
+}
+
+// SourcingComparison compares in-stock and global sourcing for one part.
+type SourcingComparison struct {
+	Product *Product
+	Stock   SourcingQuote
+	Global  SourcingQuote
+	Best    SourcingMode // Cheapest available mode; empty if neither
+}
+
+// CompareSourcing prices placing quantity parts from stock and through
+// global sourcing. Both order OrderQuantity parts. Stock wins ties, as it
+// ships without lead time.
+func (p *Product) CompareSourcing(quantity int, model *AttritionModel) SourcingComparison {
+	qty := p.OrderQuantity(quantity, model)
+	comp := SourcingComparison{
+		Product: p,
+		Stock:   SourcingQuote{Mode: SourcingStock, Quantity: qty},
+		Global:  SourcingQuote{Mode: SourcingGlobal, Quantity: qty},
+	}
+
+	if unit, ok := p.UnitPrice(qty); ok {
+		comp.Stock.UnitPrice, comp.Stock.Total = unit, unit.Mul(qty)
+		comp.Stock.Available = p.StockCount >= qty
+	}
+	if unit, ok := p.BuyUnitPrice(qty); ok && p.IsBuyable() {
+		comp.Global.UnitPrice, comp.Global.Total = unit, unit.Mul(qty)
+		comp.Global.Available = true
+		comp.Global.LeadTimeDays, _ = p.LeadTimeDays()
+	}
+
+	switch {
+	case comp.Stock.Available && comp.Global.Available:
+		comp.Best = SourcingStock
+		if comp.Global.Total.Cmp(comp.Stock.Total) < 0 {
+			comp.Best = SourcingGlobal
+		}
+	case comp.Stock.Available:
+		comp.Best = SourcingStock
+	case comp.Global.Available:
+		comp.Best = SourcingGlobal
+	}
+	return comp
+}
+
+// CompareSourcing searches in-stock and global sourcing parts and compares
+// the cost of placing quantity parts each way. req.PresaleType is ignored;
+// the first page of each search is merged by part code, stock results
+// first.
+func CompareSourcing(ctx context.Context, api PartsAPI, req SearchRequest, quantity int, model *AttritionModel) ([]SourcingComparison, error) {
+	seen := make(map[string]bool)
+	var comparisons []SourcingComparison
+	for _, mode := range []SourcingMode{SourcingStock, SourcingGlobal} {
+		req.PresaleType = string(mode)
+		resp, err := api.KeywordSearch(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for i := range resp.Products {
+			p := &resp.Products[i]
+			if seen[p.ComponentCode] {
+				continue
+			}
+			seen[p.ComponentCode] = true
+			comparisons = append(comparisons, p.CompareSourcing(quantity, model))
+		}
+	}
+	return comparisons, nil
+}
@@ -0,0 +1,175 @@
+package jlcpcb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+	"github.com/PatrickWalther/go-jlcpcb-parts/jlcpcbtest"
+)
+
+// fixture returns a built-in fixture by part code.
+func fixture(t *testing.T, code string) *jlcpcb.Product {
+	t.Helper()
+
+	for _, p := range jlcpcbtest.Fixtures() {
+		if p.ComponentCode == code {
+			return &p
+		}
+	}
+	t.Fatalf("no fixture %s", code)
+	return nil
+}
+
+// TestProductSourcing tests the typed sourcing information.
+func TestProductSourcing(t *testing.T) {
+	module := fixture(t, "C5676715")
+	module.LeadTime = "3-5 weeks"
+	s := module.Sourcing()
+	if s.Mode != jlcpcb.SourcingStock || s.InStock != 549 || !s.Global || s.LeadTimeDays != 35 || len(s.BuyPrices) != 1 {
+		t.Errorf("unexpected sourcing for C5676715: %+v", s)
+	}
+
+	if s := fixture(t, "C2040").Sourcing(); s.Mode != jlcpcb.SourcingGlobal || !s.Global {
+		t.Errorf("expected out-of-stock C2040 to use global sourcing, got %+v", s)
+	}
+	if s := fixture(t, "C25744").Sourcing(); s.Mode != jlcpcb.SourcingStock || s.Global {
+		t.Errorf("expected C25744 to be stock only, got %+v", s)
+	}
+	if s := (&jlcpcb.Product{IsBuyComponent: "0"}).Sourcing(); s.Mode != "" {
+		t.Errorf("expected no sourcing mode, got %q", s.Mode)
+	}
+}
+
+// TestLeadTimeDays tests parsing lead times.
+func TestLeadTimeDays(t *testing.T) {
+	tests := []struct {
+		lead jlcpcb.FlexString
+		days int
+		ok   bool
+	}{
+		{"14", 14, true},
+		{"7 days", 7, true},
+		{"2 weeks", 14, true},
+		{"3-5 Weeks", 35, true},
+		{"", 0, false},
+		{"unknown", 0, false},
+	}
+	for _, tt := range tests {
+		p := jlcpcb.Product{LeadTime: tt.lead}
+		if days, ok := p.LeadTimeDays(); days != tt.days || ok != tt.ok {
+			t.Errorf("LeadTimeDays(%q) = %d, %v, want %d, %v", tt.lead, days, ok, tt.days, tt.ok)
+		}
+	}
+}
+
+// TestProductCompareSourcing tests choosing between stock and global
+// sourcing by quantity.
+func TestProductCompareSourcing(t *testing.T) {
+	module := fixture(t, "C5676715")
+	module.LeadTime = "10"
+
+	tests := []struct {
+		quantity int
+		best     jlcpcb.SourcingMode
+		stock    bool
+		total    string
+	}{
+		{10, jlcpcb.SourcingGlobal, true, "39.5"},
+		{100, jlcpcb.SourcingStock, true, "352"},
+		{1000, jlcpcb.SourcingGlobal, false, "3950"},
+	}
+	for _, tt := range tests {
+		comp := module.CompareSourcing(tt.quantity, nil)
+		best := comp.Stock
+		if comp.Best == jlcpcb.SourcingGlobal {
+			best = comp.Global
+		}
+		if comp.Best != tt.best || comp.Stock.Available != tt.stock || best.Total.Decimal() != tt.total {
+			t.Errorf("CompareSourcing(%d) = %s at %s, stock available %v; want %s at %s, %v",
+				tt.quantity, comp.Best, best.Total.Decimal(), comp.Stock.Available, tt.best, tt.total, tt.stock)
+		}
+		if !comp.Global.Available || comp.Global.LeadTimeDays != 10 {
+			t.Errorf("CompareSourcing(%d): expected global sourcing in 10 days, got %+v", tt.quantity, comp.Global)
+		}
+	}
+
+	// Buyable, but out of stock and without buy prices.
+	if comp := fixture(t, "C2040").CompareSourcing(10, nil); comp.Best != "" || comp.Stock.Available || comp.Global.Available {
+		t.Errorf("expected C2040 to be unavailable, got %+v", comp)
+	}
+}
+
+// TestCompareSourcing tests searching both sourcing modes.
+func TestCompareSourcing(t *testing.T) {
+	fake := jlcpcbtest.NewFake(jlcpcbtest.Fixtures()...)
+	req := jlcpcb.SearchRequest{Keyword: "microcontrollers"}
+
+	comps, err := jlcpcb.CompareSourcing(context.Background(), fake, req, 10, nil)
+	if err != nil {
+		t.Fatalf("CompareSourcing failed: %v", err)
+	}
+
+	searches := fake.Searches()
+	if len(searches) != 2 || searches[0].PresaleType != "stock" || searches[1].PresaleType != "buy" {
+		t.Fatalf("expected stock and buy searches, got %+v", searches)
+	}
+	stock, err := fake.KeywordSearch(context.Background(), searches[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	global, err := fake.KeywordSearch(context.Background(), searches[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stock.TotalCount != 2 || global.TotalCount != 1 || global.Products[0].ComponentCode != "C2040" {
+		t.Fatalf("expected the buy search to return only C2040 of 2 parts, got %d and %+v", stock.TotalCount, global.Products)
+	}
+
+	best := make(map[string]jlcpcb.SourcingComparison)
+	for _, comp := range comps {
+		best[comp.Product.ComponentCode] = comp
+	}
+	if len(comps) != 2 {
+		t.Fatalf("expected 2 merged comparisons, got %d", len(comps))
+	}
+	if comp := best["C8734"]; comp.Best != jlcpcb.SourcingStock || comp.Global.Available {
+		t.Errorf("expected C8734 from stock only, got %+v", comp)
+	}
+	if comp := best["C2040"]; comp.Stock.Available {
+		t.Errorf("expected out-of-stock C2040 to be unavailable from stock, got %+v", comp)
+	}
+
+	fake.SetError(errors.New("boom"))
+	if _, err := jlcpcb.CompareSourcing(context.Background(), fake, jlcpcb.SearchRequest{Keyword: "x"}, 10, nil); err == nil {
+		t.Error("expected search errors to be returned")
+	}
+}
+
+// TestPreorderable tests pre-order lookups against the fake and the fake
+// server.
+func TestPreorderable(t *testing.T) {
+	server := jlcpcbtest.NewServer(jlcpcbtest.Fixtures())
+	defer server.Close()
+	client := jlcpcb.NewClient(jlcpcb.WithBaseURL(server.URL), jlcpcb.WithRateLimit(1000))
+
+	apis := map[string]jlcpcb.PartsAPI{
+		"fake":   jlcpcbtest.NewFake(jlcpcbtest.Fixtures()...),
+		"client": client,
+	}
+	for name, api := range apis {
+		for code, want := range map[string]bool{"C2040": true, "C5676715": false, "C8734": false, "C999999": false} {
+			got, err := jlcpcb.Preorderable(context.Background(), api, code)
+			if err != nil {
+				t.Errorf("%s: Preorderable(%s) failed: %v", name, code, err)
+			} else if got != want {
+				t.Errorf("%s: Preorderable(%s) = %v, want %v", name, code, got, want)
+			}
+		}
+	}
+
+	if _, err := jlcpcb.Preorderable(context.Background(), client, " "); err == nil {
+		t.Error("expected an error for an empty code")
+	}
+}
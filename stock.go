@@ -0,0 +1,165 @@
+package jlcpcb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultStockConcurrency is the default number of concurrent lookups made
+// by CheckStock.
+const defaultStockConcurrency = 4
+
+// StockLine is one part of a board and how many are placed per board.
+type StockLine struct {
+	Code     string // LCSC part code
+	PerBoard int    // Parts placed per board
+}
+
+// StockOptions configures CheckStock.
+type StockOptions struct {
+	// Concurrency limits concurrent lookups (default 4).
+	Concurrency int
+
+	// Attrition returns the extra parts consumed by machine loss when
+	// placing quantity parts. The default uses the product's LossNumber;
+	// pass an AttritionModel's Extra method to apply per-package rules.
+	Attrition func(p *Product, quantity int) int
+}
+
+// StockStatus is the stock check of one part.
+type StockStatus struct {
+	Code      string
+	PerBoard  int      // Parts placed per board
+	Product   *Product // nil if the lookup failed
+	Err       error    // Lookup error, if any
+	Placed    int      // Parts placed on all boards
+	Attrition int      // Extra parts for machine loss
+	Required  int      // Parts to order: placed plus attrition, at least the MOQ
+	Available int      // Current stock
+	Shortfall int      // Parts missing from stock; 0 if enough
+	MaxBoards int      // Most boards the stock of this part can supply
+}
+
+// StockReport is the result of CheckStock.
+type StockReport struct {
+	Boards    int           // Boards requested
+	Lines     []StockStatus // One per distinct part code, in input order
+	MaxBoards int           // Most boards buildable from current stock
+}
+
+// OK reports whether every part is in stock for the requested boards.
+func (r *StockReport) OK() bool {
+	return r.MaxBoards >= r.Boards
+}
+
+// Shortfalls returns the lines that cannot be supplied, including failed
+// lookups.
+func (r *StockReport) Shortfalls() []StockStatus {
+	var short []StockStatus
+	for _, line := range r.Lines {
+		if line.Err != nil || line.Shortfall > 0 {
+			short = append(short, line)
+		}
+	}
+	return short
+}
+
+// CheckStock reports whether JLCPCB has enough stock to build boards copies
+// of a board, and the most boards the current stock allows. Lines with the
+// same code are merged. Each part is looked up once, with lookups made
+// concurrently; pass a Client with caching enabled to reuse recent stock
+// counts across checks.
+//
+// The parts required for a line are those placed plus attrition, rounded
+// up to the part's minimum order quantity. A failed lookup is reported on
+// its line and counts as no stock; CheckStock itself only fails on invalid
+// input, including an empty list of lines, or when ctx is done.
+func CheckStock(ctx context.Context, api PartsAPI, lines []StockLine, boards int, opts StockOptions) (*StockReport, error) {
+	if boards <= 0 {
+		return nil, ErrInvalidInput{Message: "board count must be positive"}
+	}
+	if len(lines) == 0 {
+		return nil, ErrInvalidInput{Message: "at least one part is required"}
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultStockConcurrency
+	}
+	if opts.Attrition == nil {
+		opts.Attrition = (*AttritionModel)(nil).Extra
+	}
+
+	report := &StockReport{Boards: boards}
+	index := make(map[string]int)
+	for _, line := range lines {
+		if line.PerBoard <= 0 {
+			return nil, ErrInvalidInput{Message: fmt.Sprintf("quantity per board of %s must be positive", line.Code)}
+		}
+		key := strings.ToUpper(strings.TrimSpace(line.Code))
+		if key == "" {
+			return nil, ErrInvalidInput{Message: "part code is required"}
+		}
+		if i, ok := index[key]; ok {
+			report.Lines[i].PerBoard += line.PerBoard
+			continue
+		}
+		index[key] = len(report.Lines)
+		report.Lines = append(report.Lines, StockStatus{Code: line.Code, PerBoard: line.PerBoard})
+	}
+
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+	for i := range report.Lines {
+		wg.Add(1)
+		go func(status *StockStatus) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			status.Product, status.Err = api.GetProductDetails(ctx, status.Code)
+		}(&report.Lines[i])
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range report.Lines {
+		status := &report.Lines[i]
+		checkLine(status, boards, opts.Attrition)
+		if i == 0 || status.MaxBoards < report.MaxBoards {
+			report.MaxBoards = status.MaxBoards
+		}
+	}
+	return report, nil
+}
+
+// checkLine fills in the quantities of a looked-up line.
+func checkLine(status *StockStatus, boards int, attrition func(*Product, int) int) {
+	status.Placed = status.PerBoard * boards
+	if status.Product == nil {
+		status.Shortfall = status.Placed
+		return
+	}
+
+	p := status.Product
+	status.Available = p.StockCount
+	status.Attrition = attrition(p, status.Placed)
+	status.Required = requiredParts(p, status.Placed, attrition)
+	if status.Required > status.Available {
+		status.Shortfall = status.Required - status.Available
+	}
+
+	// Required parts grow with the board count, so the largest buildable
+	// count can be found by bisection.
+	status.MaxBoards = sort.Search(p.StockCount/status.PerBoard+1, func(n int) bool {
+		return requiredParts(p, (n+1)*status.PerBoard, attrition) > p.StockCount
+	})
+}
+
+// requiredParts returns the parts to order to place quantity parts.
+func requiredParts(p *Product, quantity int, attrition func(*Product, int) int) int {
+	return max(quantity+attrition(p, quantity), p.MinPurchaseNum)
+}
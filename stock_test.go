@@ -0,0 +1,132 @@
+package jlcpcb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	jlcpcb "github.com/PatrickWalther/go-jlcpcb-parts"
+	"github.com/PatrickWalther/go-jlcpcb-parts/jlcpcbtest"
+)
+
+// stockProduct returns a product with the given stock, MOQ and attrition.
+func stockProduct(code string, stock, moq, loss int) jlcpcb.Product {
+	return jlcpcb.Product{ComponentCode: code, StockCount: stock, MinPurchaseNum: moq, LossNumber: loss}
+}
+
+// TestCheckStock tests shortfalls and the buildable board count.
+func TestCheckStock(t *testing.T) {
+	fake := jlcpcbtest.NewFake(
+		stockProduct("C1", 10000, 100, 10),
+		stockProduct("C2", 250, 1, 0),
+	)
+
+	lines := []jlcpcb.StockLine{
+		{Code: "C1", PerBoard: 4},
+		{Code: "C2", PerBoard: 1},
+		{Code: "c2", PerBoard: 1},
+	}
+	report, err := jlcpcb.CheckStock(context.Background(), fake, lines, 100, jlcpcb.StockOptions{})
+	if err != nil {
+		t.Fatalf("CheckStock failed: %v", err)
+	}
+
+	if len(report.Lines) != 2 || len(fake.Lookups()) != 2 {
+		t.Fatalf("expected duplicate codes merged into 2 lookups, got %d lines and %v", len(report.Lines), fake.Lookups())
+	}
+	c1, c2 := report.Lines[0], report.Lines[1]
+	if c1.Placed != 400 || c1.Attrition != 10 || c1.Required != 410 || c1.Shortfall != 0 || c1.MaxBoards != 2497 {
+		t.Errorf("unexpected C1 status: %+v", c1)
+	}
+	if c2.PerBoard != 2 || c2.Required != 200 || c2.Shortfall != 0 || c2.MaxBoards != 125 {
+		t.Errorf("unexpected C2 status: %+v", c2)
+	}
+	if !report.OK() || report.MaxBoards != 125 || len(report.Shortfalls()) != 0 {
+		t.Errorf("expected 100 boards to be buildable, max 125, got %+v", report)
+	}
+
+	report, err = jlcpcb.CheckStock(context.Background(), fake, lines, 200, jlcpcb.StockOptions{})
+	if err != nil {
+		t.Fatalf("CheckStock failed: %v", err)
+	}
+	short := report.Shortfalls()
+	if report.OK() || len(short) != 1 || short[0].Code != "C2" || short[0].Shortfall != 150 {
+		t.Errorf("expected a C2 shortfall of 150, got %+v", short)
+	}
+}
+
+// TestCheckStockMinimumOrder tests parts whose MOQ exceeds the stock.
+func TestCheckStockMinimumOrder(t *testing.T) {
+	fake := jlcpcbtest.NewFake(stockProduct("C3", 1000, 5000, 0))
+
+	report, err := jlcpcb.CheckStock(context.Background(), fake, []jlcpcb.StockLine{{Code: "C3", PerBoard: 1}}, 10, jlcpcb.StockOptions{})
+	if err != nil {
+		t.Fatalf("CheckStock failed: %v", err)
+	}
+	line := report.Lines[0]
+	if line.Required != 5000 || line.Shortfall != 4000 || report.MaxBoards != 0 {
+		t.Errorf("expected the MOQ to block every board, got %+v", line)
+	}
+}
+
+// TestCheckStockAttrition tests a custom attrition function.
+func TestCheckStockAttrition(t *testing.T) {
+	fake := jlcpcbtest.NewFake(stockProduct("C1", 1100, 1, 0))
+	opts := jlcpcb.StockOptions{
+		Attrition: func(p *jlcpcb.Product, quantity int) int { return quantity / 10 },
+	}
+
+	report, err := jlcpcb.CheckStock(context.Background(), fake, []jlcpcb.StockLine{{Code: "C1", PerBoard: 10}}, 100, opts)
+	if err != nil {
+		t.Fatalf("CheckStock failed: %v", err)
+	}
+	if line := report.Lines[0]; line.Attrition != 100 || line.Required != 1100 || report.MaxBoards != 100 {
+		t.Errorf("expected 10%% attrition to allow exactly 100 boards, got %+v, max %d", line, report.MaxBoards)
+	}
+
+	model, err := jlcpcb.NewAttritionModel(jlcpcb.AttritionRule{Package: ".", Extra: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake = jlcpcbtest.NewFake(jlcpcb.Product{ComponentCode: "C1", ComponentSpecificationEn: "0402", StockCount: 1100, MinPurchaseNum: 1})
+	opts = jlcpcb.StockOptions{Attrition: model.Extra}
+	report, err = jlcpcb.CheckStock(context.Background(), fake, []jlcpcb.StockLine{{Code: "C1", PerBoard: 10}}, 100, opts)
+	if err != nil {
+		t.Fatalf("CheckStock failed: %v", err)
+	}
+	if line := report.Lines[0]; line.Attrition != 100 || report.MaxBoards != 100 {
+		t.Errorf("expected the model's 100 extra parts, got %+v, max %d", line, report.MaxBoards)
+	}
+}
+
+// TestCheckStockErrors tests failed lookups and invalid input.
+func TestCheckStockErrors(t *testing.T) {
+	fake := jlcpcbtest.NewFake(stockProduct("C1", 10000, 1, 0))
+
+	report, err := jlcpcb.CheckStock(context.Background(), fake, []jlcpcb.StockLine{{Code: "C1", PerBoard: 1}, {Code: "C404", PerBoard: 2}}, 5, jlcpcb.StockOptions{})
+	if err != nil {
+		t.Fatalf("CheckStock failed: %v", err)
+	}
+	var notFound jlcpcb.ErrProductNotFound
+	short := report.Shortfalls()
+	if len(short) != 1 || !errors.As(short[0].Err, &notFound) || short[0].Shortfall != 10 || report.MaxBoards != 0 {
+		t.Errorf("expected the missing part to block every board, got %+v", short)
+	}
+
+	var inputErr jlcpcb.ErrInvalidInput
+	if _, err := jlcpcb.CheckStock(context.Background(), fake, []jlcpcb.StockLine{{Code: "C1", PerBoard: 1}}, 0, jlcpcb.StockOptions{}); !errors.As(err, &inputErr) {
+		t.Errorf("expected ErrInvalidInput for zero boards, got %v", err)
+	}
+	if _, err := jlcpcb.CheckStock(context.Background(), fake, nil, 1, jlcpcb.StockOptions{}); !errors.As(err, &inputErr) {
+		t.Errorf("expected ErrInvalidInput for an empty BOM, got %v", err)
+	}
+	if _, err := jlcpcb.CheckStock(context.Background(), fake, []jlcpcb.StockLine{{Code: "C1"}}, 1, jlcpcb.StockOptions{}); !errors.As(err, &inputErr) {
+		t.Errorf("expected ErrInvalidInput for zero per board, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := jlcpcb.CheckStock(ctx, fake, []jlcpcb.StockLine{{Code: "C1", PerBoard: 1}}, 1, jlcpcb.StockOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}